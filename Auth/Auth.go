@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	logging "stima-2-be/Logging"
+	"strings"
+)
+
+const (
+	ScopeRead  = "read"
+	ScopeAdmin = "admin"
+
+	APIKeyHeader = "X-API-Key"
+	// Panjang minimal key biar ga gampang ditebak
+	MinKeyLength = 16
+)
+
+type Key struct {
+	Name   string
+	hash   [32]byte
+	scopes map[string]bool
+}
+
+// Admin otomatis boleh semua yang read
+func (k Key) Has(scope string) bool {
+	return k.scopes[scope] || k.scopes[ScopeAdmin]
+}
+
+type KeyStore struct {
+	keys []Key
+	// Kalau false, endpoint read (pencarian dll) bisa diakses tanpa key
+	requireRead bool
+}
+
+// Entri berformat "nama:key" atau cuma "key" (nama otomatis <scope>-<urutan>)
+func ParseKeys(entries []string, scope string) ([]Key, error) {
+	var keys []Key
+	for i, entry := range entries {
+		name := fmt.Sprintf("%s-%d", scope, i+1)
+		secret := entry
+		if idx := strings.Index(entry, ":"); idx >= 0 {
+			name, secret = entry[:idx], entry[idx+1:]
+		}
+		if len(secret) < MinKeyLength {
+			return nil, fmt.Errorf("api key %s terlalu pendek, minimal %d karakter", name, MinKeyLength)
+		}
+		keys = append(keys, Key{
+			Name:   name,
+			hash:   sha256.Sum256([]byte(secret)),
+			scopes: map[string]bool{scope: true},
+		})
+	}
+	return keys, nil
+}
+
+func NewKeyStore(keys []Key, requireRead bool) *KeyStore {
+	return &KeyStore{keys: keys, requireRead: requireRead}
+}
+
+func (ks *KeyStore) HasScope(scope string) bool {
+	for _, k := range ks.keys {
+		if k.Has(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cari key yang cocok, semua key dibandingin biar waktunya konstan
+func (ks *KeyStore) lookup(secret string) (Key, bool) {
+	hash := sha256.Sum256([]byte(secret))
+	var found Key
+	ok := false
+	for _, k := range ks.keys {
+		if subtle.ConstantTimeCompare(hash[:], k.hash[:]) == 1 {
+			found, ok = k, true
+		}
+	}
+	return found, ok
+}
+
+// Ambil key dari "Authorization: Bearer <key>" atau header X-API-Key
+func credential(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		if scheme, token, found := strings.Cut(header, " "); found && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return strings.TrimSpace(r.Header.Get(APIKeyHeader))
+}
+
+// Format error sama kayak handler: {"error": {"code": ..., "message": ...}}
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="stima-2-be"`)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]string{"code": code, "message": message},
+	})
+}
+
+// Middleware: request harus bawa key dengan scope tertentu.
+// Scope read cuma dicek kalau requireRead aktif
+func Require(ks *KeyStore, scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if scope == ScopeRead && !ks.requireRead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		secret := credential(r)
+		if secret == "" {
+			if !ks.HasScope(scope) {
+				writeError(w, http.StatusUnauthorized, "auth_not_configured",
+					"Endpoint ini butuh API key dengan scope "+scope+", tapi belum ada key yang dikonfigurasi di server")
+				return
+			}
+			writeError(w, http.StatusUnauthorized, "unauthorized",
+				"API key wajib dikirim lewat header Authorization: Bearer <key> atau "+APIKeyHeader)
+			return
+		}
+
+		key, ok := ks.lookup(secret)
+		if !ok {
+			logging.FromContext(r.Context()).Warn("api key tidak valid", "path", r.URL.Path)
+			writeError(w, http.StatusUnauthorized, "unauthorized", "API key tidak valid")
+			return
+		}
+		if !key.Has(scope) {
+			writeError(w, http.StatusForbidden, "forbidden", "API key tidak punya scope "+scope)
+			return
+		}
+
+		if scope == ScopeAdmin {
+			logging.FromContext(r.Context()).Info("admin request", "key", key.Name, "method", r.Method, "path", r.URL.Path)
+		}
+		next.ServeHTTP(w, r)
+	}
+}
+
+// Middleware buat route campuran: GET/HEAD pakai scope read, method lain admin
+func ByMethod(ks *KeyStore, next http.HandlerFunc) http.HandlerFunc {
+	read := Require(ks, ScopeRead, next)
+	admin := Require(ks, ScopeAdmin, next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			read.ServeHTTP(w, r)
+			return
+		}
+		admin.ServeHTTP(w, r)
+	}
+}
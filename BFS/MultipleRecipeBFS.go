@@ -6,14 +6,10 @@ import (
 	"stima-2-be/Element"
 	"strings"
 	"sync"
-	"time"
 )
 
-type MetricsResult struct {
-	NodesVisited  int64  `json:"nodes_visited"`
-	Duration      int64  `json:"duration_ms"`
-	DurationHuman string `json:"duration_human"`
-}
+// Alias biar kode lama yang pake bfs.MetricsResult tetap jalan
+type MetricsResult = Element.MetricsResult
 
 // Queue untuk BFS
 type Queue struct {
@@ -46,49 +42,60 @@ func cloneMap(original map[string]bool) map[string]bool {
 }
 
 // nyari semua resep make BFS
-func findRecipesBFS(root string, recipeMap map[string][]Element.Element, tierLimit int, limit int) ([]Element.Element, int64) {
-	var nodesVisited int64 = 0
+func findRecipesBFS(root string, ds *Element.Dataset, tierLimit int, limit int, stats *Element.SearchStats, c *Element.Constraints) []Element.Element {
 	var recipes []Element.Element
 
 	// Jika root adalah komponen dasar, return kosong
-	if Element.IsBaseComponent(root) || root == "time" {
-		return recipes, nodesVisited
+	if ds.IsBaseComponent(root) || ds.IsExcluded(root) {
+		return recipes
 	}
 
 	queue := &Queue{}
 	visited := make(map[string]bool)
 	queue.Enqueue(root)
+	stats.Push(1)
+	defer func() { stats.Pop(len(queue.items)) }()
 
-	for !queue.IsEmpty() && len(recipes) < limit {
+	for !queue.IsEmpty() && len(recipes) < limit && !stats.Stopped() {
 		current := queue.Dequeue()
+		stats.Pop(1)
 		current = strings.ToLower(current)
-		nodesVisited++
 
 		// Skip klo udah visited
 		if visited[current] {
+			stats.PruneVisited()
 			continue
 		}
+		stats.Visit(0)
 		visited[current] = true
 
-		currentRecipes, exists := recipeMap[current]
+		currentRecipes, exists := ds.RecipeMap[current]
 		if !exists {
 			continue
 		}
 
 		for _, recipe := range currentRecipes {
 			recipeInt := Element.ParseTier(recipe.Tier)
-			if recipeInt < tierLimit {
-				recipes = append(recipes, recipe)
+			if recipeInt >= tierLimit {
+				stats.PruneTier()
+				continue
+			}
 
-				left := strings.ToLower(recipe.Left)
-				right := strings.ToLower(recipe.Right)
+			left := strings.ToLower(recipe.Left)
+			right := strings.ToLower(recipe.Right)
+			if c.Excluded(left) || c.Excluded(right) {
+				stats.PruneConstraint()
+				continue
+			}
+			recipes = append(recipes, recipe)
 
-				if !visited[left] {
-					queue.Enqueue(left)
-				}
-				if !visited[right] {
-					queue.Enqueue(right)
-				}
+			if !visited[left] {
+				queue.Enqueue(left)
+				stats.Push(1)
+			}
+			if !visited[right] {
+				queue.Enqueue(right)
+				stats.Push(1)
 			}
 		}
 
@@ -98,87 +105,59 @@ func findRecipesBFS(root string, recipeMap map[string][]Element.Element, tierLim
 		}
 	}
 
-	return recipes, nodesVisited
+	return recipes
 }
 
-func buildAllTreesFromRecipe(recipe Element.Element, recipeMap map[string][]Element.Element, visited map[string]bool, tierLimit int, limit int, nodesVisited *int64) []Element.Tree {
-	*nodesVisited++
+// depth = kedalaman root resep di tree, required = elemen yang wajib ada di tree ini,
+// maxNodes = jumlah node maksimum tree ini
+func buildAllTreesFromRecipe(recipe Element.Element, ds *Element.Dataset, visited map[string]bool, tierLimit int, limit int, depth int, stats *Element.SearchStats,
+	c *Element.Constraints, required []string, maxNodes int) []Element.Tree {
+	// Budget habis atau request dibatalkan, hasil yang udah ada dibiarin
+	if stats.Stopped() {
+		return []Element.Tree{}
+	}
+	stats.Push(1)
+	defer stats.Pop(1)
 
-	newVisited := cloneMap(visited)
-	newVisited[strings.ToLower(recipe.Root)] = true
+	left := strings.ToLower(recipe.Left)
+	right := strings.ToLower(recipe.Right)
 
-	var resultTrees []Element.Tree
+	// Resep butuh root + dua bahan satu level di bawahnya
+	if c.Excluded(recipe.Root) || !c.DepthAllowed(depth+1) || maxNodes < 3 {
+		stats.PruneConstraint()
+		return []Element.Tree{}
+	}
+	leftReq, _, ok := c.SplitRequired(required, recipe.Root, left, right)
+	if !ok {
+		stats.PruneConstraint()
+		return []Element.Tree{}
+	}
+	// Baru dihitung dijelajahi setelah lolos semua pemangkasan
+	stats.Visit(depth)
 
-	left := strings.ToLower(recipe.Left)
-	var leftTrees []Element.Tree
+	newVisited := cloneMap(visited)
+	newVisited[strings.ToLower(recipe.Root)] = true
 
-	if Element.IsBaseComponent(left) {
-		*nodesVisited++
-		leftTrees = append(leftTrees, Element.Tree{
-			Root: Element.Element{
-				Root:  left,
-				Left:  "",
-				Right: "",
-				Tier:  "0",
-			},
-			Children: nil,
-		})
-	} else if !newVisited[left] {
-		leftRecipes, exists := recipeMap[left]
-		if exists {
-			for _, leftRecipe := range leftRecipes {
-				leftTierInt := Element.ParseTier(leftRecipe.Tier)
-				if leftTierInt < tierLimit {
-					subLimit := limit
-
-					leftSubtrees := buildAllTreesFromRecipe(leftRecipe, recipeMap, newVisited, leftTierInt, subLimit, nodesVisited)
-					leftTrees = append(leftTrees, leftSubtrees...)
-					if len(leftTrees) >= subLimit {
-						break
-					}
-				}
-			}
+	// Dengan constraints subtree kanan tergantung subtree kiri, jadi dicari terpisah
+	if c != nil {
+		subtrees := func(name string, limit int, required []string, maxNodes int) []Element.Tree {
+			return ingredientTrees(name, ds, newVisited, tierLimit, limit, depth, stats, c, required, maxNodes)
 		}
+		return c.Combine(recipe, limit, stats, leftReq, required, maxNodes, subtrees)
 	}
 
+	var resultTrees []Element.Tree
+
+	leftTrees := ingredientTrees(left, ds, newVisited, tierLimit, limit, depth, stats, nil, nil, maxNodes)
 	if len(leftTrees) == 0 {
 		return []Element.Tree{}
 	}
 
-	right := strings.ToLower(recipe.Right)
-	var rightTrees []Element.Tree
-
-	if Element.IsBaseComponent(right) {
-		*nodesVisited++
-		rightTrees = append(rightTrees, Element.Tree{
-			Root: Element.Element{
-				Root:  right,
-				Left:  "",
-				Right: "",
-				Tier:  "0",
-			},
-			Children: nil,
-		})
-	} else if !newVisited[right] {
-		rightRecipes, exists := recipeMap[right]
-		if exists {
-			for _, rightRecipe := range rightRecipes {
-				rightTierInt := Element.ParseTier(rightRecipe.Tier)
-				if rightTierInt < tierLimit {
-					subLimit := limit
-					if subLimit > 10 {
-						subLimit = 10
-					}
-					rightSubtrees := buildAllTreesFromRecipe(rightRecipe, recipeMap, newVisited, rightTierInt, subLimit, nodesVisited)
-					rightTrees = append(rightTrees, rightSubtrees...)
-					if len(rightTrees) >= subLimit {
-						break
-					}
-				}
-			}
-		}
+	rightLimit := limit
+	if rightLimit > 10 {
+		rightLimit = 10
 	}
-
+	rightTrees := ingredientTrees(right, ds, newVisited, tierLimit, rightLimit, depth, stats, nil, nil, maxNodes)
 	if len(rightTrees) == 0 {
 		return []Element.Tree{}
 	}
@@ -199,28 +178,75 @@ func buildAllTreesFromRecipe(recipe Element.Element, recipeMap map[string][]Elem
 	return resultTrees
 }
 
-func buildTreesBFS(root string, recipeMap map[string][]Element.Element, limit int) ([]Element.Tree, int64) {
-	if Element.IsBaseComponent(root) {
-		return []Element.Tree{
-			{
-				Root: Element.Element{
-					Root:  root,
-					Left:  "",
-					Right: "",
-					Tier:  "0",
-				},
-				Children: nil,
+// Subtree untuk satu bahan resep di kedalaman depth+1, berhenti setelah dapet limit tree
+func ingredientTrees(name string, ds *Element.Dataset, visited map[string]bool, tierLimit int, limit int, depth int, stats *Element.SearchStats,
+	c *Element.Constraints, required []string, maxNodes int) []Element.Tree {
+	var trees []Element.Tree
+
+	if c.Excluded(name) || maxNodes < 1 {
+		stats.PruneConstraint()
+	} else if ds.IsBaseComponent(name) {
+		leaf := Element.Tree{
+			Root: Element.Element{
+				Root:  name,
+				Left:  "",
+				Right: "",
+				Tier:  "0",
+			},
+			Children: nil,
+		}
+		if c.Satisfies(leaf, required) {
+			stats.Visit(depth + 1)
+			trees = append(trees, leaf)
+		} else {
+			stats.PruneConstraint()
+		}
+	} else if !visited[name] {
+		recipes, exists := ds.RecipeMap[name]
+		if exists {
+			for _, recipe := range recipes {
+				tierInt := Element.ParseTier(recipe.Tier)
+				if tierInt >= tierLimit {
+					stats.PruneTier()
+					continue
+				}
+				subtrees := buildAllTreesFromRecipe(recipe, ds, visited, tierInt, limit, depth+1, stats, c, required, maxNodes)
+				trees = append(trees, subtrees...)
+				if len(trees) >= limit || stats.Stopped() {
+					break
+				}
+			}
+		}
+	} else {
+		stats.PruneVisited()
+	}
+
+	return trees
+}
+
+func buildTreesBFS(root string, ds *Element.Dataset, limit int, stats *Element.SearchStats, c *Element.Constraints) []Element.Tree {
+	if ds.IsBaseComponent(root) {
+		leaf := Element.Tree{
+			Root: Element.Element{
+				Root:  root,
+				Left:  "",
+				Right: "",
+				Tier:  "0",
 			},
-		}, 1
+			Children: nil,
+		}
+		if c.Excluded(root) || !c.Satisfies(leaf, c.Required()) {
+			stats.PruneConstraint()
+			return nil
+		}
+		stats.Visit(0)
+		return []Element.Tree{leaf}
 	}
 
-	var nodesVisited int64 = 0
 	var resultTrees []Element.Tree
 
-	recipes, visitedCount := findRecipesBFS(root, recipeMap, math.MaxInt32, limit*2)
-	nodesVisited += visitedCount
+	recipes := findRecipesBFS(root, ds, math.MaxInt32, limit*2, stats, c)
 
-	var mu sync.Mutex
 	var wg sync.WaitGroup
 
 	treeChan := make(chan []Element.Tree, len(recipes))
@@ -231,20 +257,14 @@ func buildTreesBFS(root string, recipeMap map[string][]Element.Element, limit in
 		}
 
 		wg.Add(1)
+		stats.Spawn(1)
 		go func(r Element.Element) {
 			defer wg.Done()
 
 			visited := make(map[string]bool)
 			tierInt := Element.ParseTier(r.Tier)
-			var localVisited int64 = 0
-
-			trees := buildAllTreesFromRecipe(r, recipeMap, visited, tierInt, limit, &localVisited)
 
-			mu.Lock()
-			nodesVisited += localVisited
-			mu.Unlock()
-
-			treeChan <- trees
+			treeChan <- buildAllTreesFromRecipe(r, ds, visited, tierInt, limit, 0, stats, c, c.Required(), c.NodeBudget())
 		}(recipe)
 	}
 
@@ -263,45 +283,28 @@ func buildTreesBFS(root string, recipeMap map[string][]Element.Element, limit in
 		}
 	}
 
-	return resultTrees, nodesVisited
+	return resultTrees
 }
 
-func MultipleRecipe(name string, recipeMap map[string][]Element.Element, count int) ([]Element.Tree, MetricsResult) {
-	startTime := time.Now()
+func MultipleRecipe(name string, ds *Element.Dataset, count int) ([]Element.Tree, MetricsResult) {
+	return MultipleRecipeWithConstraints(name, ds, count, nil)
+}
+
+// Sama seperti MultipleRecipe, cabang yang melanggar constraints dipangkas selama pencarian
+func MultipleRecipeWithConstraints(name string, ds *Element.Dataset, count int, c *Element.Constraints) ([]Element.Tree, MetricsResult) {
+	return MultipleRecipeWithStats(name, ds, count, c, Element.NewSearchStats())
+}
 
+// Versi lengkap dengan stats dari pemanggil, misalnya stats dengan budget node dari /batch
+func MultipleRecipeWithStats(name string, ds *Element.Dataset, count int, c *Element.Constraints, stats *Element.SearchStats) ([]Element.Tree, MetricsResult) {
 	name = strings.ToLower(name)
-	var trees []Element.Tree
-	var nodesVisited int64
-
-	if Element.IsBaseComponent(name) {
-		trees = []Element.Tree{
-			{
-				Root: Element.Element{
-					Root:  name,
-					Left:  "",
-					Right: "",
-					Tier:  "0",
-				},
-				Children: nil,
-			},
-		}
-		nodesVisited = 1
-	} else {
-		trees, nodesVisited = buildTreesBFS(name, recipeMap, count)
-	}
+	trees := buildTreesBFS(name, ds, count, stats, c)
 
 	if len(trees) > count {
 		trees = trees[:count]
 	}
 
-	duration := time.Since(startTime)
-	metrics := MetricsResult{
-		NodesVisited:  nodesVisited,
-		Duration:      duration.Milliseconds(),
-		DurationHuman: duration.String(),
-	}
-
-	return trees, metrics
+	return trees, stats.Result()
 }
 
 func PrintTree(t Element.Tree, indent string) {
@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Response yang disimpan, body udah jadi tinggal ditulis ulang
+type Entry struct {
+	Body           []byte
+	ContentType    string
+	DatasetVersion string
+}
+
+type item struct {
+	key   string
+	entry Entry
+}
+
+type Stats struct {
+	Entries    int    `json:"entries"`
+	Bytes      int64  `json:"bytes"`
+	MaxEntries int    `json:"max_entries"`
+	MaxBytes   int64  `json:"max_bytes"`
+	Hits       uint64 `json:"hits"`
+	Misses     uint64 `json:"misses"`
+	Evictions  uint64 `json:"evictions"`
+}
+
+// LRU dibatasi jumlah entri dan total ukuran body.
+// Cache nil berarti nonaktif, semua method aman dipanggil
+type Cache struct {
+	maxEntries int
+	maxBytes   int64
+
+	mu        sync.Mutex
+	ll        *list.List
+	items     map[string]*list.Element
+	bytes     int64
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// Balikin nil kalau maxEntries <= 0, maxBytes <= 0 berarti ukuran ga dibatasi
+func New(maxEntries int, maxBytes int64) *Cache {
+	if maxEntries <= 0 {
+		return nil
+	}
+	return &Cache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (c *Cache) Get(key string) (Entry, bool) {
+	if c == nil {
+		return Entry{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, exists := c.items[key]
+	if !exists {
+		c.misses++
+		return Entry{}, false
+	}
+	c.hits++
+	c.ll.MoveToFront(el)
+	return el.Value.(*item).entry, true
+}
+
+func (c *Cache) Put(key string, entry Entry) {
+	if c == nil {
+		return
+	}
+	size := int64(len(entry.Body))
+	// Body yang lebih gede dari seluruh cache ga usah disimpan
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, exists := c.items[key]; exists {
+		c.bytes += size - int64(len(el.Value.(*item).entry.Body))
+		el.Value.(*item).entry = entry
+		c.ll.MoveToFront(el)
+	} else {
+		c.items[key] = c.ll.PushFront(&item{key: key, entry: entry})
+		c.bytes += size
+	}
+
+	for c.ll.Len() > c.maxEntries || (c.maxBytes > 0 && c.bytes > c.maxBytes) {
+		c.removeOldest()
+	}
+}
+
+func (c *Cache) removeOldest() {
+	el := c.ll.Back()
+	if el == nil {
+		return
+	}
+	it := el.Value.(*item)
+	c.ll.Remove(el)
+	delete(c.items, it.key)
+	c.bytes -= int64(len(it.entry.Body))
+	c.evictions++
+}
+
+// Kosongin cache, dipanggil tiap dataset berubah
+func (c *Cache) Purge() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+	c.bytes = 0
+}
+
+func (c *Cache) Stats() Stats {
+	if c == nil {
+		return Stats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{
+		Entries:    c.ll.Len(),
+		Bytes:      c.bytes,
+		MaxEntries: c.maxEntries,
+		MaxBytes:   c.maxBytes,
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+	}
+}
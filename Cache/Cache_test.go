@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+)
+
+func entry(size int) Entry {
+	return Entry{Body: []byte(strings.Repeat("x", size)), ContentType: "application/json"}
+}
+
+func TestEvictByEntries(t *testing.T) {
+	c := New(2, 0)
+	c.Put("a", entry(1))
+	c.Put("b", entry(1))
+	// a jadi paling baru dipakai, jadi b yang dibuang
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a harus ada")
+	}
+	c.Put("c", entry(1))
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b harusnya udah dibuang")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("%s harus masih ada", key)
+		}
+	}
+	if stats := c.Stats(); stats.Entries != 2 || stats.Evictions != 1 {
+		t.Errorf("stats = %+v, mau 2 entri dan 1 eviction", stats)
+	}
+}
+
+func TestEvictByBytes(t *testing.T) {
+	c := New(10, 10)
+	c.Put("a", entry(4))
+	c.Put("b", entry(4))
+	c.Put("c", entry(4))
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("a harusnya dibuang karena total melebihi max bytes")
+	}
+	if stats := c.Stats(); stats.Bytes != 8 || stats.Entries != 2 {
+		t.Errorf("stats = %+v, mau 8 byte dan 2 entri", stats)
+	}
+
+	// Body yang lebih gede dari seluruh cache ga disimpan dan ga ngebuang entri lain
+	c.Put("big", entry(11))
+	if _, ok := c.Get("big"); ok {
+		t.Error("body di atas max bytes ga boleh disimpan")
+	}
+	if stats := c.Stats(); stats.Entries != 2 {
+		t.Errorf("entri = %d, mau tetap 2", stats.Entries)
+	}
+}
+
+func TestPutReplaceUpdatesBytes(t *testing.T) {
+	c := New(10, 0)
+	c.Put("a", entry(4))
+	c.Put("a", entry(7))
+	if stats := c.Stats(); stats.Bytes != 7 || stats.Entries != 1 {
+		t.Errorf("stats = %+v, mau 7 byte dan 1 entri", stats)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	c := New(10, 0)
+	c.Put("a", entry(4))
+	c.Purge()
+	if _, ok := c.Get("a"); ok {
+		t.Error("a harusnya hilang setelah Purge")
+	}
+	if stats := c.Stats(); stats.Bytes != 0 || stats.Entries != 0 {
+		t.Errorf("stats = %+v, mau kosong", stats)
+	}
+}
+
+func TestNilCache(t *testing.T) {
+	c := New(0, 0)
+	if c != nil {
+		t.Fatal("New(0, 0) harus nil")
+	}
+	c.Put("a", entry(1))
+	if _, ok := c.Get("a"); ok {
+		t.Error("cache nil ga boleh nyimpen")
+	}
+	c.Purge()
+	if stats := c.Stats(); stats != (Stats{}) {
+		t.Errorf("stats cache nil = %+v", stats)
+	}
+}
@@ -0,0 +1,526 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"runtime"
+	auth "stima-2-be/Auth"
+	limit "stima-2-be/Limit"
+	logging "stima-2-be/Logging"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// File config dipakai kalau -config / CONFIG_FILE ga diisi (boleh ga ada)
+const DefaultFile = "config.json"
+
+// Sumber nilai, urutan prioritas: flag > env > file > default
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+type Config struct {
+	Port       int
+	CORSOrigin string
+	WikiURL    string
+
+	// Timeout http.Server dan batas waktu drain saat shutdown
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+
+	OutputFile  string
+	DiffFile    string
+	DatasetDir  string
+	SnapshotDir string
+	PackDir     string
+	// Jumlah snapshot terakhir yang disimpan, yang lebih lama dihapus
+	MaxSnapshots int
+
+	BaseComponents     []string
+	ExcludedComponents []string
+	// /readyz gagal kalau dataset default kurang dari ini
+	ReadyMinElements int
+
+	LogLevel  string
+	LogFormat string
+
+	Limits limit.Config
+
+	// Cache response pencarian, CacheMaxEntries 0 = cache mati
+	CacheMaxEntries int
+	CacheMaxBytes   int
+
+	// Batas POST /batch
+	BatchMaxQueries  int
+	BatchConcurrency int
+	BatchNodeBudget  int
+
+	// API key per scope, format "nama:key" atau "key"
+	AdminAPIKeys    []string
+	ReadAPIKeys     []string
+	RequireReadAuth bool
+
+	// Asal tiap nilai (key config -> Source*)
+	Sources map[string]string
+	// File config yang kebaca, kosong kalau ga ada
+	File string
+	// -print-config: cetak konfigurasi lalu keluar
+	PrintOnly bool
+}
+
+// Satu entri konfigurasi: key file, nama flag, nama env dan cara set/get nilainya
+type field struct {
+	key   string
+	flag  string
+	env   string
+	usage string
+	set   func(c *Config, v string) error
+	get   func(c *Config) string
+	// Flag boolean boleh ditulis tanpa nilai (-trust-proxy)
+	isBool bool
+}
+
+func parseList(v string) []string {
+	var result []string
+	for _, item := range strings.Split(v, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func stringField(key, flagName, env, usage string, ptr func(c *Config) *string) field {
+	return field{key: key, flag: flagName, env: env, usage: usage,
+		set: func(c *Config, v string) error { *ptr(c) = v; return nil },
+		get: func(c *Config) string { return *ptr(c) }}
+}
+
+func intField(key, flagName, env, usage string, ptr func(c *Config) *int) field {
+	return field{key: key, flag: flagName, env: env, usage: usage,
+		set: func(c *Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("harus bilangan bulat: %q", v)
+			}
+			*ptr(c) = n
+			return nil
+		},
+		get: func(c *Config) string { return strconv.Itoa(*ptr(c)) }}
+}
+
+func floatField(key, flagName, env, usage string, ptr func(c *Config) *float64) field {
+	return field{key: key, flag: flagName, env: env, usage: usage,
+		set: func(c *Config, v string) error {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return fmt.Errorf("harus angka: %q", v)
+			}
+			*ptr(c) = f
+			return nil
+		},
+		get: func(c *Config) string { return strconv.FormatFloat(*ptr(c), 'g', -1, 64) }}
+}
+
+func boolField(key, flagName, env, usage string, ptr func(c *Config) *bool) field {
+	return field{key: key, flag: flagName, env: env, usage: usage, isBool: true,
+		set: func(c *Config, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("harus true/false: %q", v)
+			}
+			*ptr(c) = b
+			return nil
+		},
+		get: func(c *Config) string { return strconv.FormatBool(*ptr(c)) }}
+}
+
+func durationField(key, flagName, env, usage string, ptr func(c *Config) *time.Duration) field {
+	return field{key: key, flag: flagName, env: env, usage: usage,
+		set: func(c *Config, v string) error {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("harus durasi, misalnya 10s: %q", v)
+			}
+			*ptr(c) = d
+			return nil
+		},
+		get: func(c *Config) string { return ptr(c).String() }}
+}
+
+// List rahasia: huruf besar/kecil dipertahankan dan nilainya ga pernah dicetak
+func secretListField(key, flagName, env, usage string, ptr func(c *Config) *[]string) field {
+	return field{key: key, flag: flagName, env: env, usage: usage,
+		set: func(c *Config, v string) error {
+			var result []string
+			for _, item := range strings.Split(v, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					result = append(result, item)
+				}
+			}
+			*ptr(c) = result
+			return nil
+		},
+		get: func(c *Config) string { return fmt.Sprintf("<%d key>", len(*ptr(c))) }}
+}
+
+func listField(key, flagName, env, usage string, ptr func(c *Config) *[]string) field {
+	return field{key: key, flag: flagName, env: env, usage: usage,
+		set: func(c *Config, v string) error { *ptr(c) = parseList(v); return nil },
+		get: func(c *Config) string { return strings.Join(*ptr(c), ",") }}
+}
+
+var fields = []field{
+	intField("port", "port", "PORT", "port HTTP server",
+		func(c *Config) *int { return &c.Port }),
+	stringField("cors_origin", "cors-origin", "CORS_ORIGIN", "nilai header Access-Control-Allow-Origin",
+		func(c *Config) *string { return &c.CORSOrigin }),
+	stringField("wiki_url", "wiki-url", "WIKI_URL", "halaman wiki yang di-scrape",
+		func(c *Config) *string { return &c.WikiURL }),
+	durationField("read_timeout", "read-timeout", "READ_TIMEOUT", "batas waktu membaca request (0 = tanpa batas)",
+		func(c *Config) *time.Duration { return &c.ReadTimeout }),
+	durationField("read_header_timeout", "read-header-timeout", "READ_HEADER_TIMEOUT", "batas waktu membaca header request",
+		func(c *Config) *time.Duration { return &c.ReadHeaderTimeout }),
+	durationField("write_timeout", "write-timeout", "WRITE_TIMEOUT", "batas waktu menulis response, termasuk lama pencarian",
+		func(c *Config) *time.Duration { return &c.WriteTimeout }),
+	durationField("idle_timeout", "idle-timeout", "IDLE_TIMEOUT", "batas waktu koneksi keep-alive menganggur",
+		func(c *Config) *time.Duration { return &c.IdleTimeout }),
+	durationField("shutdown_timeout", "shutdown-timeout", "SHUTDOWN_TIMEOUT", "batas waktu menunggu request yang sedang jalan saat shutdown",
+		func(c *Config) *time.Duration { return &c.ShutdownTimeout }),
+	stringField("output_file", "output-file", "OUTPUT_FILE", "file output.json versi lama, cuma dibaca sebagai pembanding diff kalau belum ada snapshot (tidak ditulis lagi)",
+		func(c *Config) *string { return &c.OutputFile }),
+	stringField("diff_file", "diff-file", "DIFF_FILE", "file hasil diff scrape terakhir",
+		func(c *Config) *string { return &c.DiffFile }),
+	stringField("dataset_dir", "dataset-dir", "DATASET_DIR", "folder dataset tambahan",
+		func(c *Config) *string { return &c.DatasetDir }),
+	stringField("snapshot_dir", "snapshot-dir", "SNAPSHOT_DIR", "folder snapshot hasil scrape",
+		func(c *Config) *string { return &c.SnapshotDir }),
+	intField("max_snapshots", "max-snapshots", "MAX_SNAPSHOTS", "jumlah snapshot terakhir yang disimpan",
+		func(c *Config) *int { return &c.MaxSnapshots }),
+	stringField("pack_dir", "pack-dir", "PACK_DIR", "folder recipe pack",
+		func(c *Config) *string { return &c.PackDir }),
+	listField("base_components", "base-components", "BASE_COMPONENTS", "base component dataset default, pisahkan dengan koma",
+		func(c *Config) *[]string { return &c.BaseComponents }),
+	listField("excluded_components", "excluded-components", "EXCLUDED_COMPONENTS", "elemen yang tidak boleh dipakai di resep, pisahkan dengan koma",
+		func(c *Config) *[]string { return &c.ExcludedComponents }),
+	intField("ready_min_elements", "ready-min-elements", "READY_MIN_ELEMENTS", "minimal elemen dataset default agar /readyz sukses",
+		func(c *Config) *int { return &c.ReadyMinElements }),
+	stringField("log_level", "log-level", "LOG_LEVEL", "level log: debug, info, warn, error",
+		func(c *Config) *string { return &c.LogLevel }),
+	stringField("log_format", "log-format", "LOG_FORMAT", "format log: json, text",
+		func(c *Config) *string { return &c.LogFormat }),
+	floatField("rate_limit", "rate-limit", "RATE_LIMIT", "request per detik per IP (0 = tanpa limit)",
+		func(c *Config) *float64 { return &c.Limits.Rate }),
+	intField("rate_burst", "rate-burst", "RATE_BURST", "burst request per IP",
+		func(c *Config) *int { return &c.Limits.Burst }),
+	floatField("scrap_rate_limit", "scrap-rate-limit", "SCRAP_RATE_LIMIT", "request /Scrap per detik per IP",
+		func(c *Config) *float64 { return &c.Limits.ScrapRate }),
+	intField("scrap_rate_burst", "scrap-rate-burst", "SCRAP_RATE_BURST", "burst request /Scrap per IP",
+		func(c *Config) *int { return &c.Limits.ScrapBurst }),
+	intField("max_concurrent_searches", "max-concurrent-searches", "MAX_CONCURRENT_SEARCHES", "pencarian yang boleh jalan bersamaan",
+		func(c *Config) *int { return &c.Limits.MaxConcurrent }),
+	intField("search_queue_size", "search-queue-size", "SEARCH_QUEUE_SIZE", "panjang antrian pencarian",
+		func(c *Config) *int { return &c.Limits.QueueSize }),
+	durationField("search_queue_timeout", "search-queue-timeout", "SEARCH_QUEUE_TIMEOUT", "lama maksimum menunggu di antrian",
+		func(c *Config) *time.Duration { return &c.Limits.QueueTimeout }),
+	boolField("trust_proxy", "trust-proxy", "TRUST_PROXY", "pakai X-Forwarded-For untuk IP client",
+		func(c *Config) *bool { return &c.Limits.TrustProxy }),
+	intField("cache_max_entries", "cache-max-entries", "CACHE_MAX_ENTRIES", "jumlah response pencarian yang di-cache (0 = cache mati)",
+		func(c *Config) *int { return &c.CacheMaxEntries }),
+	intField("cache_max_bytes", "cache-max-bytes", "CACHE_MAX_BYTES", "total ukuran body di cache dalam byte (0 = tanpa batas)",
+		func(c *Config) *int { return &c.CacheMaxBytes }),
+	intField("batch_max_queries", "batch-max-queries", "BATCH_MAX_QUERIES", "jumlah query maksimum per POST /batch",
+		func(c *Config) *int { return &c.BatchMaxQueries }),
+	intField("batch_concurrency", "batch-concurrency", "BATCH_CONCURRENCY", "query yang dikerjakan bersamaan dalam satu batch",
+		func(c *Config) *int { return &c.BatchConcurrency }),
+	intField("batch_node_budget", "batch-node-budget", "BATCH_NODE_BUDGET", "total node yang boleh dikunjungi satu batch (0 = tanpa batas)",
+		func(c *Config) *int { return &c.BatchNodeBudget }),
+	secretListField("admin_api_keys", "admin-api-keys", "ADMIN_API_KEYS", "API key scope admin (scrape, pack, snapshot), pisahkan dengan koma",
+		func(c *Config) *[]string { return &c.AdminAPIKeys }),
+	secretListField("read_api_keys", "read-api-keys", "READ_API_KEYS", "API key scope read, pisahkan dengan koma",
+		func(c *Config) *[]string { return &c.ReadAPIKeys }),
+	boolField("require_read_auth", "require-read-auth", "REQUIRE_READ_AUTH", "endpoint pencarian juga wajib pakai API key",
+		func(c *Config) *bool { return &c.RequireReadAuth }),
+}
+
+func Default() Config {
+	return Config{
+		Port:               8080,
+		CORSOrigin:         "*",
+		WikiURL:            "https://little-alchemy.fandom.com/wiki/Elements_(Little_Alchemy_2)",
+		ReadTimeout:        15 * time.Second,
+		ReadHeaderTimeout:  5 * time.Second,
+		WriteTimeout:       2 * time.Minute,
+		IdleTimeout:        2 * time.Minute,
+		ShutdownTimeout:    30 * time.Second,
+		OutputFile:         "output.json",
+		DiffFile:           "diff.json",
+		DatasetDir:         "datasets",
+		SnapshotDir:        "data/snapshots",
+		MaxSnapshots:       10,
+		PackDir:            "data/packs",
+		BaseComponents:     []string{"air", "earth", "fire", "water"},
+		ExcludedComponents: []string{"time"},
+		ReadyMinElements:   1,
+		LogLevel:           "info",
+		LogFormat:          "json",
+		Limits:             limit.DefaultConfig(),
+		CacheMaxEntries:    512,
+		CacheMaxBytes:      64 << 20,
+		BatchMaxQueries:    500,
+		BatchConcurrency:   runtime.NumCPU(),
+		BatchNodeBudget:    5000000,
+		Sources:            make(map[string]string),
+	}
+}
+
+// Isi dari file JSON, key sama dengan nama di tabel fields (snake_case)
+func (c *Config) loadFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("file config %s tidak valid: %w", filename, err)
+	}
+
+	for key, value := range raw {
+		f, ok := lookup(key)
+		if !ok {
+			return fmt.Errorf("file config %s: key tidak dikenal: %s", filename, key)
+		}
+		text, err := rawToString(value)
+		if err != nil {
+			return fmt.Errorf("file config %s: %s: %w", filename, key, err)
+		}
+		if err := f.set(c, text); err != nil {
+			return fmt.Errorf("file config %s: %s %w", filename, key, err)
+		}
+		c.Sources[key] = SourceFile
+	}
+	c.File = filename
+	return nil
+}
+
+// Nilai JSON (string, angka, bool, array string) dijadiin teks biar setter-nya sama
+func rawToString(value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s, nil
+	}
+	var list []string
+	if err := json.Unmarshal(value, &list); err == nil {
+		return strings.Join(list, ","), nil
+	}
+	var scalar interface{}
+	if err := json.Unmarshal(value, &scalar); err != nil {
+		return "", err
+	}
+	switch scalar.(type) {
+	case float64, bool:
+		return string(value), nil
+	}
+	return "", errors.New("harus string, angka, boolean atau array string")
+}
+
+func lookup(key string) (field, bool) {
+	for _, f := range fields {
+		if f.key == key {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// Baca konfigurasi dari default, file, env lalu flag (args tanpa nama program)
+func Load(args []string) (Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("stima-2-be", flag.ContinueOnError)
+	configFile := fs.String("config", "", "file konfigurasi JSON (default "+DefaultFile+" kalau ada, atau env CONFIG_FILE)")
+	fs.BoolVar(&cfg.PrintOnly, "print-config", false, "cetak konfigurasi efektif lalu keluar")
+
+	// Flag baru diterapkan setelah file dan env supaya prioritasnya paling tinggi
+	type pending struct {
+		f     field
+		value string
+	}
+	var flagValues []pending
+	for _, f := range fields {
+		f := f
+		collect := func(v string) error {
+			flagValues = append(flagValues, pending{f, v})
+			return nil
+		}
+		if f.isBool {
+			fs.BoolFunc(f.flag, f.usage+" (env "+f.env+")", collect)
+		} else {
+			fs.Func(f.flag, f.usage+" (env "+f.env+")", collect)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	filename := *configFile
+	if filename == "" {
+		filename = os.Getenv("CONFIG_FILE")
+	}
+	if filename != "" {
+		if err := cfg.loadFile(filename); err != nil {
+			return cfg, err
+		}
+	} else if _, err := os.Stat(DefaultFile); err == nil {
+		if err := cfg.loadFile(DefaultFile); err != nil {
+			return cfg, err
+		}
+	}
+
+	for _, f := range fields {
+		if v, ok := os.LookupEnv(f.env); ok && v != "" {
+			if err := f.set(&cfg, v); err != nil {
+				return cfg, fmt.Errorf("env %s %w", f.env, err)
+			}
+			cfg.Sources[f.key] = SourceEnv
+		}
+	}
+
+	for _, p := range flagValues {
+		if err := p.f.set(&cfg, p.value); err != nil {
+			return cfg, fmt.Errorf("flag -%s %w", p.f.flag, err)
+		}
+		cfg.Sources[p.f.key] = SourceFlag
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// Cek semua nilai, semua kesalahan dikumpulin sekaligus
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Port > 0 && c.Port <= 65535, "port harus 1-65535: %d", c.Port)
+	check(c.CORSOrigin != "", "cors_origin wajib diisi")
+	if u, err := url.Parse(c.WikiURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("wiki_url harus URL http/https: %q", c.WikiURL))
+	}
+	check(c.ReadTimeout >= 0, "read_timeout tidak boleh negatif")
+	check(c.ReadHeaderTimeout >= 0, "read_header_timeout tidak boleh negatif")
+	check(c.WriteTimeout >= 0, "write_timeout tidak boleh negatif")
+	check(c.IdleTimeout >= 0, "idle_timeout tidak boleh negatif")
+	check(c.ShutdownTimeout > 0, "shutdown_timeout harus lebih dari 0")
+	// Pencarian bisa antri dulu, jangan sampai response dipotong sebelum antriannya selesai
+	check(c.WriteTimeout == 0 || c.WriteTimeout > c.Limits.QueueTimeout,
+		"write_timeout (%s) harus lebih besar dari search_queue_timeout (%s)", c.WriteTimeout, c.Limits.QueueTimeout)
+	check(c.OutputFile != "", "output_file wajib diisi")
+	check(c.DiffFile != "", "diff_file wajib diisi")
+	check(c.DatasetDir != "", "dataset_dir wajib diisi")
+	check(c.SnapshotDir != "", "snapshot_dir wajib diisi")
+	// Snapshot aktif ga pernah dibuang, jadi minimal harus muat snapshot aktif + hasil scrape baru
+	check(c.MaxSnapshots >= 2, "max_snapshots minimal 2")
+	check(c.PackDir != "", "pack_dir wajib diisi")
+	check(len(c.BaseComponents) > 0, "base_components minimal satu elemen")
+	check(c.ReadyMinElements >= 1, "ready_min_elements minimal 1")
+	for _, name := range c.ExcludedComponents {
+		for _, base := range c.BaseComponents {
+			check(name != base, "%s tidak boleh sekaligus base dan excluded component", name)
+		}
+	}
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		errs = append(errs, err)
+	}
+	format := strings.ToLower(c.LogFormat)
+	check(format == "json" || format == "text", "log_format harus json atau text: %q", c.LogFormat)
+
+	check(c.Limits.Rate >= 0, "rate_limit tidak boleh negatif")
+	check(c.Limits.Burst >= 0, "rate_burst tidak boleh negatif")
+	check(c.Limits.ScrapRate >= 0, "scrap_rate_limit tidak boleh negatif")
+	check(c.Limits.ScrapBurst >= 0, "scrap_rate_burst tidak boleh negatif")
+	check(c.Limits.MaxConcurrent >= 1, "max_concurrent_searches minimal 1")
+	check(c.Limits.QueueSize >= 0, "search_queue_size tidak boleh negatif")
+	check(c.Limits.QueueTimeout >= 0, "search_queue_timeout tidak boleh negatif")
+	check(c.CacheMaxEntries >= 0, "cache_max_entries tidak boleh negatif")
+	check(c.CacheMaxBytes >= 0, "cache_max_bytes tidak boleh negatif")
+	check(c.BatchMaxQueries >= 1, "batch_max_queries minimal 1")
+	check(c.BatchConcurrency >= 1, "batch_concurrency minimal 1")
+	check(c.BatchNodeBudget >= 0, "batch_node_budget tidak boleh negatif")
+
+	if _, err := c.KeyStore(); err != nil {
+		errs = append(errs, err)
+	}
+	check(!c.RequireReadAuth || len(c.AdminAPIKeys)+len(c.ReadAPIKeys) > 0,
+		"require_read_auth aktif tapi belum ada read_api_keys/admin_api_keys")
+
+	return errors.Join(errs...)
+}
+
+// Gabungan key admin dan read
+func (c *Config) KeyStore() (*auth.KeyStore, error) {
+	admin, err := auth.ParseKeys(c.AdminAPIKeys, auth.ScopeAdmin)
+	if err != nil {
+		return nil, err
+	}
+	read, err := auth.ParseKeys(c.ReadAPIKeys, auth.ScopeRead)
+	if err != nil {
+		return nil, err
+	}
+	return auth.NewKeyStore(append(admin, read...), c.RequireReadAuth), nil
+}
+
+// Map base component versi Element (excluded = false)
+func (c *Config) BaseComponentMap() map[string]bool {
+	base := make(map[string]bool)
+	for _, name := range c.BaseComponents {
+		base[name] = true
+	}
+	for _, name := range c.ExcludedComponents {
+		base[name] = false
+	}
+	return base
+}
+
+func (c *Config) source(key string) string {
+	if src, ok := c.Sources[key]; ok {
+		return src
+	}
+	return SourceDefault
+}
+
+// Pasangan key-value buat log startup
+func (c *Config) LogAttrs() []interface{} {
+	attrs := make([]interface{}, 0, len(fields)*2)
+	for _, f := range fields {
+		attrs = append(attrs, f.key, f.get(c))
+	}
+	return attrs
+}
+
+// Cetak konfigurasi efektif beserta sumbernya
+func (c *Config) Print(w io.Writer) {
+	if c.File != "" {
+		fmt.Fprintf(w, "# file config: %s\n", c.File)
+	}
+	for _, f := range fields {
+		fmt.Fprintf(w, "%-24s = %-40s # %s\n", f.key, f.get(c), c.source(f.key))
+	}
+}
@@ -6,14 +6,10 @@ import (
 	"stima-2-be/Element"
 	"strings"
 	"sync"
-	"time"
 )
 
-type MetricsResult struct {
-	NodesVisited  int64  `json:"nodes_visited"`
-	Duration      int64  `json:"duration_ms"`
-	DurationHuman string `json:"duration_human"`
-}
+// Alias biar kode lama yang pake dfs.MetricsResult tetap jalan
+type MetricsResult = Element.MetricsResult
 
 // Menghitung jumlah node pada 1 tree
 func CountNodes(tree Element.Tree) int64 {
@@ -34,27 +30,60 @@ func CloneVisited(visited map[string]bool) map[string]bool {
 }
 
 // Cari Tree yang Valid
-func BuildTrees(root string, recipeMap map[string][]Element.Element, visited map[string]bool, tierLimit int, limit int) []Element.Tree {
-	if Element.IsBaseComponent(root) {
-		return []Element.Tree{
-			{
-				Root: Element.Element{
-					Root:  root,
-					Left:  "",
-					Right: "",
-					Tier:  "0",
-				},
-				Children: nil,
+func BuildTrees(root string, ds *Element.Dataset, visited map[string]bool, tierLimit int, limit int) []Element.Tree {
+	return buildTrees(root, ds, visited, tierLimit, limit, 0, Element.NewSearchStats(), nil, nil, math.MaxInt32)
+}
+
+// Versi BuildTrees yang nyatet metrics dan nerapin constraints.
+// depth = kedalaman root di tree, required = elemen yang wajib ada di subtree ini,
+// maxNodes = jumlah node maksimum subtree ini
+func buildTrees(root string, ds *Element.Dataset, visited map[string]bool, tierLimit int, limit int, depth int, stats *Element.SearchStats,
+	c *Element.Constraints, required []string, maxNodes int) []Element.Tree {
+	// Budget habis atau request dibatalkan, hasil yang udah ada dibiarin
+	if stats.Stopped() {
+		return nil
+	}
+	stats.Push(1)
+	defer stats.Pop(1)
+
+	if c.Excluded(root) || !c.DepthAllowed(depth) || maxNodes < 1 {
+		stats.PruneConstraint()
+		return nil
+	}
+
+	if ds.IsBaseComponent(root) {
+		leaf := Element.Tree{
+			Root: Element.Element{
+				Root:  root,
+				Left:  "",
+				Right: "",
+				Tier:  "0",
 			},
+			Children: nil,
+		}
+		if !c.Satisfies(leaf, required) {
+			stats.PruneConstraint()
+			return nil
 		}
+		stats.Visit(depth)
+		return []Element.Tree{leaf}
+	}
+
+	// Elemen non-base butuh satu level lagi dan minimal 3 node
+	if !c.DepthAllowed(depth+1) || maxNodes < 3 {
+		stats.PruneConstraint()
+		return nil
 	}
 
 	if visited[root] {
+		stats.PruneVisited()
 		return nil
 	}
+	// Baru dihitung dijelajahi setelah lolos semua pemangkasan
+	stats.Visit(depth)
 
-	recipes, exists := recipeMap[strings.ToLower(root)]
-	if !exists || root == "time" {
+	recipes, exists := ds.RecipeMap[strings.ToLower(root)]
+	if !exists || ds.IsExcluded(root) {
 		return nil
 	}
 
@@ -63,14 +92,36 @@ func BuildTrees(root string, recipeMap map[string][]Element.Element, visited map
 	defer func() { visited[root] = false }()
 
 	for _, recipe := range recipes {
+		if stats.Stopped() {
+			break
+		}
 		tierInt := Element.ParseTier(recipe.Tier)
 		if tierInt >= tierLimit {
+			stats.PruneTier()
 			continue
 		}
 
 		left := strings.ToLower(recipe.Left)
 		right := strings.ToLower(recipe.Right)
 
+		leftReq, _, ok := c.SplitRequired(required, root, left, right)
+		if !ok {
+			stats.PruneConstraint()
+			continue
+		}
+
+		// Dengan constraints subtree kanan tergantung subtree kiri, jadi dicari terpisah
+		if c != nil {
+			subtrees := func(name string, limit int, required []string, maxNodes int) []Element.Tree {
+				return buildTrees(name, ds, CloneVisited(visited), tierInt, limit, depth+1, stats, c, required, maxNodes)
+			}
+			result = append(result, c.Combine(recipe, limit-len(result), stats, leftReq, required, maxNodes, subtrees)...)
+			if len(result) >= limit {
+				return result
+			}
+			continue
+		}
+
 		var leftTrees, rightTrees []Element.Tree
 		var wg sync.WaitGroup
 
@@ -78,11 +129,12 @@ func BuildTrees(root string, recipeMap map[string][]Element.Element, visited map
 		rightChan := make(chan []Element.Tree, 1)
 
 		wg.Add(2)
+		stats.Spawn(2)
 
 		// Proses kiri secara paralel
 		go func() {
 			defer wg.Done()
-			leftChan <- BuildTrees(left, recipeMap, CloneVisited(visited), tierInt, limit)
+			leftChan <- buildTrees(left, ds, CloneVisited(visited), tierInt, limit, depth+1, stats, nil, nil, maxNodes)
 		}()
 
 		// Proses kanan setelah dapat hasil subtree kiri
@@ -95,7 +147,7 @@ func BuildTrees(root string, recipeMap map[string][]Element.Element, visited map
 				return
 			}
 			rightLimit := int(math.Ceil(float64(limit) / float64(len(leftResult))))
-			rightChan <- BuildTrees(right, recipeMap, CloneVisited(visited), tierInt, rightLimit)
+			rightChan <- buildTrees(right, ds, CloneVisited(visited), tierInt, rightLimit, depth+1, stats, nil, nil, maxNodes)
 		}()
 
 		wg.Wait()
@@ -126,58 +178,30 @@ func BuildTrees(root string, recipeMap map[string][]Element.Element, visited map
 }
 
 // Perhitungan node dan pengecekan kondisi tree yang dapat dibangun (base/not)
-func MultipleRecipeConcurrent(name string, recipeMap map[string][]Element.Element, count int) ([]Element.Tree, MetricsResult) {
-	startTime := time.Now()
-	var nodesVisited int64 = 0
-	var baseComp bool
+func MultipleRecipeConcurrent(name string, ds *Element.Dataset, count int) ([]Element.Tree, MetricsResult) {
+	return MultipleRecipeWithConstraints(name, ds, count, nil)
+}
+
+// Sama seperti MultipleRecipeConcurrent, cabang yang melanggar constraints dipangkas selama pencarian
+func MultipleRecipeWithConstraints(name string, ds *Element.Dataset, count int, c *Element.Constraints) ([]Element.Tree, MetricsResult) {
+	return MultipleRecipeWithStats(name, ds, count, c, Element.NewSearchStats())
+}
+
+// Versi lengkap dengan stats dari pemanggil, misalnya stats dengan budget node dari /batch
+func MultipleRecipeWithStats(name string, ds *Element.Dataset, count int, c *Element.Constraints, stats *Element.SearchStats) ([]Element.Tree, MetricsResult) {
 	name = strings.ToLower(name)
-	var trees []Element.Tree
-	if Element.IsBaseComponent(name) {
-		baseComp = true
-		trees = []Element.Tree{
-			{
-				Root: Element.Element{
-					Root:  name,
-					Left:  "",
-					Right: "",
-					Tier:  "0",
-				},
-				Children: nil,
-			},
-		}
-	} else {
-		baseComp = false
-		trees = BuildTrees(name, recipeMap, map[string]bool{}, math.MaxInt32, count)
-	}
+	trees := buildTrees(name, ds, map[string]bool{}, math.MaxInt32, count, 0, stats, c, c.Required(), c.NodeBudget())
 
 	if len(trees) > count {
 		trees = trees[:count]
 	}
 
-	for _, tree := range trees {
-		nodesVisited += CountNodes(tree)
-	}
-
-	if baseComp {
-		duration := time.Since(startTime)
-		metrics := MetricsResult{
-			NodesVisited:  1,
-			Duration:      duration.Milliseconds(),
-			DurationHuman: duration.String()}
-		return trees, metrics
-	} else {
-		duration := time.Since(startTime)
-		metrics := MetricsResult{
-			NodesVisited:  nodesVisited,
-			Duration:      duration.Milliseconds(),
-			DurationHuman: duration.String()}
-		return trees, metrics
-	}
+	return trees, stats.Result()
 }
 
 // Convenience method untuk manggil fungsi lain
-func MultipleRecipe(name string, recipeMap map[string][]Element.Element, count int) ([]Element.Tree, MetricsResult) {
-	return MultipleRecipeConcurrent(name, recipeMap, count)
+func MultipleRecipe(name string, ds *Element.Dataset, count int) ([]Element.Tree, MetricsResult) {
+	return MultipleRecipeConcurrent(name, ds, count)
 }
 
 func PrintTree(t Element.Tree, indent string) {
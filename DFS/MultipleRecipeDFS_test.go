@@ -0,0 +1,39 @@
+package dfs
+
+import (
+	"stima-2-be/Element"
+	"stima-2-be/Element/elementtest"
+	"testing"
+)
+
+// Call yang langsung dipangkas ga boleh kehitung sebagai node yang dijelajahi
+func TestPrunedCallsNotVisited(t *testing.T) {
+	ds := elementtest.ConstraintsDataset()
+	exclude := Element.NewConstraints(ds, []string{"steam"}, nil, 0, 0)
+
+	tests := []struct {
+		name    string
+		root    string
+		visited map[string]bool
+		c       *Element.Constraints
+	}{
+		{"visited", "a", map[string]bool{"a": true}, nil},
+		{"exclude", "steam", map[string]bool{}, exclude},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := Element.NewSearchStats()
+			trees := buildTrees(tt.root, ds, tt.visited, 100, 1, 0, stats, tt.c, nil, 100)
+			if len(trees) != 0 {
+				t.Fatalf("dapet %d tree, mau 0", len(trees))
+			}
+			result := stats.Result()
+			if result.NodesVisited != 0 {
+				t.Errorf("nodes_visited = %d, mau 0", result.NodesVisited)
+			}
+			if result.PrunedByVisited+result.PrunedByConstraint != 1 {
+				t.Errorf("pruned = %+v, mau 1", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package Element
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Elemen antara yang bisa dipakai bareng untuk dua target
+type SharedIngredient struct {
+	Element string `json:"element"`
+	Tier    int    `json:"tier"`
+	// Jumlah langkah crafting resep termurah (subtree yang sama dihitung ulang)
+	Cost int  `json:"cost"`
+	Tree Tree `json:"tree"`
+}
+
+type planKey struct {
+	name  string
+	limit int
+}
+
+type planResult struct {
+	cost int
+	tree Tree
+	ok   bool
+}
+
+// Cari tree resep termurah dengan aturan tier yang sama seperti BFS/DFS
+// (tier resep bahan harus lebih kecil dari tier resep induknya).
+// Elemen di reuse dianggap sudah dibuat, biayanya 0 dan subtree-nya dipakai ulang
+type planner struct {
+	ds    *Dataset
+	reuse map[string]Tree
+	memo  map[planKey]planResult
+}
+
+func newPlanner(ds *Dataset, reuse []Tree) *planner {
+	p := &planner{ds: ds, reuse: make(map[string]Tree), memo: make(map[planKey]planResult)}
+	for _, t := range reuse {
+		p.addReuse(t)
+	}
+	return p
+}
+
+func (p *planner) addReuse(t Tree) {
+	if len(t.Children) == 0 {
+		return
+	}
+	p.reuse[strings.ToLower(t.Root.Root)] = t
+	for _, child := range t.Children {
+		p.addReuse(child)
+	}
+}
+
+func leafTree(name string) Tree {
+	return Tree{Root: Element{Root: name, Tier: "0"}}
+}
+
+func (p *planner) cheapest(name string, limit int) planResult {
+	key := strings.ToLower(name)
+	if p.ds.IsBaseComponent(key) {
+		return planResult{tree: leafTree(name), ok: true}
+	}
+	if t, exists := p.reuse[key]; exists && ParseTier(t.Root.Tier) < limit {
+		return planResult{tree: t, ok: true}
+	}
+
+	memoKey := planKey{key, limit}
+	if result, exists := p.memo[memoKey]; exists {
+		return result
+	}
+
+	var best planResult
+	recipes, exists := p.ds.RecipeMap[key]
+	if exists && !p.ds.IsExcluded(key) {
+		for _, recipe := range recipes {
+			tier := ParseTier(recipe.Tier)
+			if tier >= limit || recipe.Left == "" || recipe.Right == "" {
+				continue
+			}
+			left := p.cheapest(recipe.Left, tier)
+			if !left.ok {
+				continue
+			}
+			right := p.cheapest(recipe.Right, tier)
+			if !right.ok {
+				continue
+			}
+			cost := 1 + left.cost + right.cost
+			if !best.ok || cost < best.cost {
+				best = planResult{
+					cost: cost,
+					tree: Tree{Root: recipe, Children: []Tree{left.tree, right.tree}},
+					ok:   true,
+				}
+			}
+		}
+	}
+	p.memo[memoKey] = best
+	return best
+}
+
+// Tree resep termurah untuk satu elemen, elemen yang ada di tree reuse dianggap sudah dibuat.
+// Balikin false kalau elemen ga bisa dibuat dari base component
+func (d *Dataset) CheapestTree(name string, reuse ...Tree) (Tree, int, bool) {
+	result := newPlanner(d, reuse).cheapest(name, math.MaxInt32)
+	return result.tree, result.cost, result.ok
+}
+
+// Semua elemen (lowercase) yang bisa muncul di tree resep valid suatu elemen, termasuk dirinya.
+// Resep yang bahannya ga bisa dibuat ga ikut dihitung
+func (d *Dataset) Closure(name string) map[string]bool {
+	p := newPlanner(d, nil)
+	closure := make(map[string]bool)
+	seen := make(map[planKey]bool)
+
+	var walk func(name string, limit int)
+	walk = func(name string, limit int) {
+		key := strings.ToLower(name)
+		if seen[planKey{key, limit}] || !p.cheapest(name, limit).ok {
+			return
+		}
+		seen[planKey{key, limit}] = true
+		closure[key] = true
+		if d.IsBaseComponent(key) {
+			return
+		}
+		for _, recipe := range d.RecipeMap[key] {
+			tier := ParseTier(recipe.Tier)
+			if tier >= limit || recipe.Left == "" || recipe.Right == "" {
+				continue
+			}
+			if !p.cheapest(recipe.Left, tier).ok || !p.cheapest(recipe.Right, tier).ok {
+				continue
+			}
+			walk(recipe.Left, tier)
+			walk(recipe.Right, tier)
+		}
+	}
+	walk(name, math.MaxInt32)
+	return closure
+}
+
+// Elemen antara yang ada di closure a dan b beserta resep termurahnya
+// (urut dari yang paling mahal, penghematan terbesar di atas), plus base component yang dipakai bareng
+func (d *Dataset) SharedIngredients(a, b string) ([]SharedIngredient, []string) {
+	closureA := d.Closure(a)
+	closureB := d.Closure(b)
+	p := newPlanner(d, nil)
+
+	shared := []SharedIngredient{}
+	bases := []string{}
+	for key := range closureA {
+		if !closureB[key] {
+			continue
+		}
+		name := d.DisplayName(key)
+		if name == "" {
+			name = key
+		}
+		if d.IsBaseComponent(key) {
+			bases = append(bases, name)
+			continue
+		}
+		result := p.cheapest(name, math.MaxInt32)
+		tier, _ := d.Tier(key)
+		shared = append(shared, SharedIngredient{Element: name, Tier: tier, Cost: result.cost, Tree: result.tree})
+	}
+
+	sort.Slice(shared, func(i, j int) bool {
+		if shared[i].Cost != shared[j].Cost {
+			return shared[i].Cost > shared[j].Cost
+		}
+		return shared[i].Element < shared[j].Element
+	})
+	sort.Strings(bases)
+	return shared, bases
+}
+
+// Langkah di rencana gabungan: Left + Right → Result, For berisi target yang butuh hasil langkah ini
+type PlanStep struct {
+	Number int      `json:"step"`
+	Left   string   `json:"left"`
+	Right  string   `json:"right"`
+	Result string   `json:"result"`
+	For    []string `json:"for"`
+}
+
+// Rencana crafting dua target sekaligus, elemen antara yang sama cukup dibuat sekali
+type CombinedPlan struct {
+	Trees         map[string]Tree `json:"trees"`
+	Steps         []PlanStep      `json:"steps"`
+	TotalSteps    int             `json:"total_steps"`
+	SeparateSteps int             `json:"separate_steps"`
+	SavedSteps    int             `json:"saved_steps"`
+}
+
+// Rencana gabungan termurah untuk a dan b. Dua urutan dicoba (b pakai ulang hasil a,
+// atau sebaliknya) dan diambil yang langkahnya paling sedikit.
+// Kalau salah satu ga bisa dibuat, namanya dibalikin di missing
+func (d *Dataset) PlanTwo(a, b string) (plan CombinedPlan, missing string) {
+	treeA, _, ok := d.CheapestTree(a)
+	if !ok {
+		return plan, a
+	}
+	treeB, _, ok := d.CheapestTree(b)
+	if !ok {
+		return plan, b
+	}
+
+	reuseB, _, _ := d.CheapestTree(b, treeA)
+	plan.Trees = map[string]Tree{a: treeA, b: reuseB}
+	plan.Steps = CombinedSteps([]string{a, b}, []Tree{treeA, reuseB})
+
+	reuseA, _, _ := d.CheapestTree(a, treeB)
+	if steps := CombinedSteps([]string{b, a}, []Tree{treeB, reuseA}); len(steps) < len(plan.Steps) {
+		plan.Trees = map[string]Tree{a: reuseA, b: treeB}
+		plan.Steps = steps
+	}
+
+	plan.TotalSteps = len(plan.Steps)
+	plan.SeparateSteps = len(CombinedSteps([]string{a}, []Tree{treeA})) + len(CombinedSteps([]string{b}, []Tree{treeB}))
+	plan.SavedSteps = plan.SeparateSteps - plan.TotalSteps
+	return plan, ""
+}
+
+// Gabungin tree beberapa target jadi satu urutan langkah. Elemen antara yang udah
+// dibuat untuk target sebelumnya ga dibuat ulang, cukup ditandai dipakai juga
+func CombinedSteps(targets []string, trees []Tree) []PlanStep {
+	b := &planBuilder{steps: []PlanStep{}, crafted: make(map[string]int)}
+	for i, t := range trees {
+		b.collect(t, targets[i])
+	}
+	return b.steps
+}
+
+type planBuilder struct {
+	steps []PlanStep
+	// Tree yang dipakai tiap langkah, buat nandain bahan kalau hasilnya dipakai ulang
+	trees   []Tree
+	crafted map[string]int
+}
+
+func (b *planBuilder) collect(t Tree, target string) {
+	if len(t.Children) == 0 {
+		return
+	}
+	if idx, exists := b.crafted[strings.ToLower(t.Root.Root)]; exists {
+		b.mark(idx, target)
+		return
+	}
+
+	for _, child := range t.Children {
+		b.collect(child, target)
+	}
+	b.crafted[strings.ToLower(t.Root.Root)] = len(b.steps)
+	b.trees = append(b.trees, t)
+
+	b.steps = append(b.steps, PlanStep{
+		Number: len(b.steps) + 1,
+		Left:   t.Root.Left,
+		Right:  t.Root.Right,
+		Result: t.Root.Root,
+		For:    []string{target},
+	})
+}
+
+// Tandai langkah (dan bahan-bahannya) sebagai kebutuhan target ini juga
+func (b *planBuilder) mark(idx int, target string) {
+	for _, name := range b.steps[idx].For {
+		if name == target {
+			return
+		}
+	}
+	b.steps[idx].For = append(b.steps[idx].For, target)
+	for _, child := range b.trees[idx].Children {
+		if childIdx, exists := b.crafted[strings.ToLower(child.Root.Root)]; exists && len(child.Children) > 0 {
+			b.mark(childIdx, target)
+		}
+	}
+}
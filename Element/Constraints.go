@@ -0,0 +1,257 @@
+package Element
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Batasan pencarian resep, dicek selama pencarian (bukan filter hasil akhir).
+// Nilai nol berarti ga dibatasi, Constraints nil juga aman dipakai
+type Constraints struct {
+	// Elemen (lowercase) yang ga boleh muncul di mana pun di tree
+	Exclude map[string]bool
+	// Elemen (lowercase) yang wajib muncul di tree
+	Require []string
+	// Kedalaman tree maksimum, root ada di kedalaman 0
+	MaxDepth int
+	// Jumlah node maksimum per tree
+	MaxNodes int
+
+	// elemen wajib -> elemen yang tree resepnya mungkin memuat elemen wajib itu
+	reach map[string]map[string]bool
+}
+
+// Balikin nil kalau ga ada batasan sama sekali
+func NewConstraints(ds *Dataset, exclude []string, require []string, maxDepth int, maxNodes int) *Constraints {
+	if len(exclude) == 0 && len(require) == 0 && maxDepth <= 0 && maxNodes <= 0 {
+		return nil
+	}
+
+	c := &Constraints{
+		Exclude:  make(map[string]bool),
+		MaxDepth: maxDepth,
+		MaxNodes: maxNodes,
+		reach:    make(map[string]map[string]bool),
+	}
+	for _, name := range exclude {
+		c.Exclude[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range require {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		c.Require = append(c.Require, key)
+	}
+	sort.Strings(c.Require)
+
+	if len(c.Require) > 0 {
+		// Index bahan -> elemen hasil, buat jalan mundur dari elemen wajib
+		usedIn := make(map[string][]string)
+		for _, e := range ds.Elements {
+			if e.Left == "" || e.Right == "" {
+				continue
+			}
+			root := strings.ToLower(e.Root)
+			usedIn[strings.ToLower(e.Left)] = append(usedIn[strings.ToLower(e.Left)], root)
+			usedIn[strings.ToLower(e.Right)] = append(usedIn[strings.ToLower(e.Right)], root)
+		}
+		for _, required := range c.Require {
+			ancestors := make(map[string]bool)
+			queue := []string{required}
+			for len(queue) > 0 {
+				current := queue[0]
+				queue = queue[1:]
+				for _, parent := range usedIn[current] {
+					if !ancestors[parent] {
+						ancestors[parent] = true
+						queue = append(queue, parent)
+					}
+				}
+			}
+			c.reach[required] = ancestors
+		}
+	}
+	return c
+}
+
+func (c *Constraints) Excluded(name string) bool {
+	return c != nil && c.Exclude[strings.ToLower(name)]
+}
+
+// Node di kedalaman depth masih boleh ada
+func (c *Constraints) DepthAllowed(depth int) bool {
+	return c == nil || c.MaxDepth <= 0 || depth <= c.MaxDepth
+}
+
+// Elemen wajib untuk tree di level paling atas
+func (c *Constraints) Required() []string {
+	if c == nil {
+		return nil
+	}
+	return c.Require
+}
+
+// Batas node awal untuk satu tree
+func (c *Constraints) NodeBudget() int {
+	if c == nil || c.MaxNodes <= 0 {
+		return math.MaxInt32
+	}
+	return c.MaxNodes
+}
+
+// Tree resep yang berakar di node mungkin memuat elemen wajib
+func (c *Constraints) canContain(node string, required string) bool {
+	return node == required || c.reach[required][node]
+}
+
+// Bagi elemen wajib subtree root ke bahan kiri/kanan. Elemen yang cuma bisa dicapai
+// lewat satu bahan jadi wajib di subtree bahan itu, yang bisa lewat dua-duanya dicek
+// waktu subtree digabung. ok false kalau ada elemen wajib yang ga mungkin dicapai
+func (c *Constraints) SplitRequired(required []string, root, left, right string) (leftReq []string, rightReq []string, ok bool) {
+	if c == nil {
+		return nil, nil, true
+	}
+	root, left, right = strings.ToLower(root), strings.ToLower(left), strings.ToLower(right)
+	for _, name := range required {
+		if name == root {
+			continue
+		}
+		viaLeft := c.canContain(left, name)
+		viaRight := c.canContain(right, name)
+		switch {
+		case viaLeft && !viaRight:
+			leftReq = append(leftReq, name)
+		case viaRight && !viaLeft:
+			rightReq = append(rightReq, name)
+		case !viaLeft && !viaRight:
+			return nil, nil, false
+		}
+	}
+	return leftReq, rightReq, true
+}
+
+// Semua elemen wajib ada di tree
+func (c *Constraints) Satisfies(t Tree, required []string) bool {
+	for _, name := range required {
+		if !treeContains(t, name) {
+			return false
+		}
+	}
+	return true
+}
+
+func treeContains(t Tree, name string) bool {
+	if strings.EqualFold(t.Root.Root, name) {
+		return true
+	}
+	for _, child := range t.Children {
+		if treeContains(child, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Elemen wajib yang belum ada di subtree kiri, jadi harus dipenuhi subtree kanan.
+// ok false kalau ada yang ga mungkin dicapai lewat bahan kanan
+func (c *Constraints) RightRequired(required []string, root string, right string, left Tree) (rightReq []string, ok bool) {
+	if c == nil {
+		return nil, true
+	}
+	root, right = strings.ToLower(root), strings.ToLower(right)
+	for _, name := range required {
+		if name == root || treeContains(left, name) {
+			continue
+		}
+		if !c.canContain(right, name) {
+			return nil, false
+		}
+		rightReq = append(rightReq, name)
+	}
+	return rightReq, true
+}
+
+// Sisa budget node untuk subtree kanan setelah root dan subtree kiri dipakai
+func (c *Constraints) RightBudget(budget int, left Tree) int {
+	if c == nil || c.MaxNodes <= 0 {
+		return budget
+	}
+	return budget - 1 - left.Size()
+}
+
+// Subtree kiri yang valid sendiri belum tentu punya pasangan kanan yang muat,
+// jadi jumlah subtree kiri yang dicoba boleh diperbesar sampai batas ini
+const constraintExpand = 16
+
+// Batas jumlah subtree kiri yang dicoba untuk dapet limit kombinasi
+func (c *Constraints) expandLimit(limit int) int {
+	if c == nil {
+		return limit
+	}
+	return limit * constraintExpand
+}
+
+// Pencari subtree satu bahan dari BFS/DFS: paling banyak limit tree yang memuat
+// semua elemen required dan paling banyak maxNodes node
+type SubtreeFunc func(name string, limit int, required []string, maxNodes int) []Tree
+
+// Kombinasi kiri x kanan satu resep saat ada constraints. Subtree kanan dicari per subtree kiri
+// pakai sisa budget node dan elemen wajib yang belum ada di kiri, jadi tiap kombinasi pasti valid
+// dan baru dihitung ke limit setelah valid. Kalau kombinasinya belum cukup, subtree kiri yang dicoba diperbanyak
+func (c *Constraints) Combine(recipe Element, limit int, stats *SearchStats, leftReq []string, required []string, maxNodes int, subtrees SubtreeFunc) []Tree {
+	left := strings.ToLower(recipe.Left)
+	right := strings.ToLower(recipe.Right)
+
+	for leftLimit := limit; ; leftLimit *= 2 {
+		var result []Tree
+		// Bahan kanan minimal 1 node, sisanya boleh dipakai subtree kiri
+		leftTrees := subtrees(left, leftLimit, leftReq, maxNodes-2)
+
+		rightCache := make(map[string][]Tree)
+		for _, lt := range leftTrees {
+			rightReq, ok := c.RightRequired(required, recipe.Root, right, lt)
+			if !ok {
+				stats.PruneConstraint()
+				continue
+			}
+			budget := c.RightBudget(maxNodes, lt)
+			key := fmt.Sprint(budget, rightReq)
+			rightTrees, seen := rightCache[key]
+			if !seen {
+				rightTrees = subtrees(right, limit, rightReq, budget)
+				rightCache[key] = rightTrees
+			}
+			for _, rt := range rightTrees {
+				result = append(result, Tree{Root: recipe, Children: []Tree{lt, rt}})
+				if len(result) >= limit {
+					return result
+				}
+			}
+		}
+
+		// Subtree kiri udah habis dicoba atau udah sampai batas
+		if len(leftTrees) < leftLimit || leftLimit >= c.expandLimit(limit) || stats.Stopped() {
+			return result
+		}
+	}
+}
+
+// Bentuk kanonik buat key cache, kosong kalau ga ada batasan
+func (c *Constraints) Key() string {
+	if c == nil {
+		return ""
+	}
+	exclude := make([]string, 0, len(c.Exclude))
+	for name := range c.Exclude {
+		exclude = append(exclude, name)
+	}
+	sort.Strings(exclude)
+	return fmt.Sprintf("exclude=%s;require=%s;max_depth=%d;max_nodes=%d",
+		strings.Join(exclude, ","), strings.Join(c.Require, ","), c.MaxDepth, c.MaxNodes)
+}
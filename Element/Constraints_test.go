@@ -0,0 +1,93 @@
+package Element_test
+
+import (
+	"reflect"
+	"stima-2-be/Element"
+	"stima-2-be/Element/elementtest"
+	"testing"
+)
+
+func TestNewConstraintsEmpty(t *testing.T) {
+	if c := Element.NewConstraints(elementtest.ConstraintsDataset(), nil, nil, 0, 0); c != nil {
+		t.Fatalf("NewConstraints tanpa batasan = %+v, mau nil", c)
+	}
+}
+
+func TestSplitRequired(t *testing.T) {
+	ds := elementtest.ConstraintsDataset()
+	tests := []struct {
+		name      string
+		require   []string
+		root      string
+		left      string
+		right     string
+		wantLeft  []string
+		wantRight []string
+		wantOK    bool
+	}{
+		{"cuma lewat kiri", []string{"steam"}, "T", "A", "B", []string{"steam"}, nil, true},
+		{"cuma lewat kanan", []string{"b"}, "T", "A", "B", nil, []string{"b"}, true},
+		{"lewat dua-duanya dicek belakangan", []string{"fire"}, "T", "A", "B", nil, nil, true},
+		{"root sendiri", []string{"t"}, "T", "A", "B", nil, nil, true},
+		{"ga kecapai", []string{"water"}, "B", "Fire", "Air", nil, nil, false},
+		{"bahan base", []string{"air"}, "A", "Air", "Earth", []string{"air"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Element.NewConstraints(ds, nil, tt.require, 0, 0)
+			left, right, ok := c.SplitRequired(c.Required(), tt.root, tt.left, tt.right)
+			if ok != tt.wantOK || !reflect.DeepEqual(left, tt.wantLeft) || !reflect.DeepEqual(right, tt.wantRight) {
+				t.Errorf("SplitRequired = (%v, %v, %v), mau (%v, %v, %v)", left, right, ok, tt.wantLeft, tt.wantRight, tt.wantOK)
+			}
+		})
+	}
+
+	var c *Element.Constraints
+	if _, _, ok := c.SplitRequired(nil, "T", "A", "B"); !ok {
+		t.Error("SplitRequired pada constraints nil harus ok")
+	}
+}
+
+func TestRightRequired(t *testing.T) {
+	ds := elementtest.ConstraintsDataset()
+	c := Element.NewConstraints(ds, nil, []string{"fire", "steam"}, 0, 0)
+	withSteam := elementtest.Node("A", "Steam", "Earth", elementtest.Node("Steam", "Water", "Fire", elementtest.Leaf("water"), elementtest.Leaf("fire")), elementtest.Leaf("earth"))
+	withoutSteam := elementtest.Node("A", "Air", "Earth", elementtest.Leaf("air"), elementtest.Leaf("earth"))
+
+	if got, ok := c.RightRequired(c.Required(), "T", "B", withSteam); !ok || len(got) != 0 {
+		t.Errorf("kiri udah lengkap: RightRequired = (%v, %v), mau ([], true)", got, ok)
+	}
+	// Steam ga bisa dicapai lewat B, jadi kiri tanpa Steam ga punya pasangan
+	if got, ok := c.RightRequired(c.Required(), "T", "B", withoutSteam); ok {
+		t.Errorf("RightRequired = (%v, %v), mau ok false", got, ok)
+	}
+
+	c = Element.NewConstraints(ds, nil, []string{"fire"}, 0, 0)
+	if got, ok := c.RightRequired(c.Required(), "T", "B", withoutSteam); !ok || !reflect.DeepEqual(got, []string{"fire"}) {
+		t.Errorf("RightRequired = (%v, %v), mau ([fire], true)", got, ok)
+	}
+}
+
+func TestRightBudget(t *testing.T) {
+	ds := elementtest.ConstraintsDataset()
+	small := elementtest.Node("A", "Air", "Earth", elementtest.Leaf("air"), elementtest.Leaf("earth"))
+	if got := Element.NewConstraints(ds, nil, nil, 0, 7).RightBudget(7, small); got != 3 {
+		t.Errorf("RightBudget = %d, mau 3", got)
+	}
+	if got := Element.NewConstraints(ds, []string{"steam"}, nil, 0, 0).RightBudget(100, small); got != 100 {
+		t.Errorf("RightBudget tanpa max_nodes = %d, mau 100", got)
+	}
+}
+
+func TestConstraintsKey(t *testing.T) {
+	ds := elementtest.ConstraintsDataset()
+	a := Element.NewConstraints(ds, []string{"Steam", "air"}, []string{"B"}, 2, 0)
+	b := Element.NewConstraints(ds, []string{"AIR", "steam"}, []string{"b"}, 2, 0)
+	if a.Key() != b.Key() {
+		t.Errorf("Key beda untuk constraints yang sama: %q vs %q", a.Key(), b.Key())
+	}
+	var c *Element.Constraints
+	if c.Key() != "" {
+		t.Errorf("Key constraints nil = %q, mau kosong", c.Key())
+	}
+}
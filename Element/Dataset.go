@@ -0,0 +1,214 @@
+package Element
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	logging "stima-2-be/Logging"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Dataset default hasil scrape Little Alchemy 2
+const DefaultDataset = "la2"
+
+// Satu dataset game, punya elemen, base component, dan recipe map sendiri
+type Dataset struct {
+	Name           string
+	Version        string
+	Elements       []Element
+	BaseComponents map[string]bool
+	RecipeMap      map[string][]Element
+
+	// lowercase -> nama asli
+	names map[string]string
+	index []indexEntry
+}
+
+// Format file dataset tambahan di folder datasets/
+type datasetFile struct {
+	Name           string          `json:"name"`
+	Version        string          `json:"version"`
+	BaseComponents map[string]bool `json:"base_components"`
+	Elements       []Element       `json:"elements"`
+}
+
+var (
+	datasets   = make(map[string]*Dataset)
+	datasetsMu sync.RWMutex
+
+	// Naik tiap kali dataset atau pack berubah, dipake buat invalidasi cache
+	generation atomic.Uint64
+	listeners  []func()
+	listenerMu sync.Mutex
+)
+
+// Dataset default selalu ada walau belum ada snapshot
+func init() {
+	RegisterDataset(NewDataset(DefaultDataset, "", nil, BaseComponents))
+}
+
+func NewDataset(name string, version string, elements []Element, baseComponents map[string]bool) *Dataset {
+	base := make(map[string]bool)
+	for k, v := range baseComponents {
+		base[strings.ToLower(k)] = v
+	}
+	names := make(map[string]string)
+	for _, e := range elements {
+		key := strings.ToLower(e.Root)
+		if _, exists := names[key]; !exists && key != "" {
+			names[key] = e.Root
+		}
+	}
+	return &Dataset{
+		Name:           name,
+		Version:        version,
+		Elements:       elements,
+		BaseComponents: base,
+		RecipeMap:      BuildRecipeMap(elements),
+		names:          names,
+		index:          buildSearchIndex(elements),
+	}
+}
+
+// Cek apakah base component di dataset ini
+func (d *Dataset) IsBaseComponent(item string) bool {
+	return d.BaseComponents[strings.ToLower(item)]
+}
+
+// Elemen awal yang sengaja ga dipake (contoh 'time' di LA2)
+func (d *Dataset) IsExcluded(item string) bool {
+	isBase, listed := d.BaseComponents[strings.ToLower(item)]
+	return listed && !isBase
+}
+
+// Semua recipe untuk rootName, log debug lewat logger request biar ada request_id-nya
+func (d *Dataset) GetElements(ctx context.Context, rootName string) []Element {
+	var result []Element
+	for _, elem := range d.Elements {
+		if strings.EqualFold(elem.Root, rootName) {
+			result = append(result, elem)
+		}
+	}
+	logging.FromContext(ctx).Debug("lookup elemen", "dataset", d.Name, "root", rootName, "found", len(result))
+	return result
+}
+
+// Validasi bahwa leaf node adalah base component dataset ini
+func (d *Dataset) ValidateTree(tree Tree) bool {
+	if len(tree.Children) == 0 {
+		return d.IsBaseComponent(tree.Root.Root)
+	}
+
+	for _, child := range tree.Children {
+		if !d.ValidateTree(child) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func RegisterDataset(d *Dataset) {
+	datasetsMu.Lock()
+	datasets[strings.ToLower(d.Name)] = d
+	datasetsMu.Unlock()
+	notifyChange()
+}
+
+// Daftarin fungsi yang dipanggil tiap dataset atau pack berubah
+// (reload snapshot, load dataset, upload/hapus pack)
+func OnChange(fn func()) {
+	listenerMu.Lock()
+	defer listenerMu.Unlock()
+	listeners = append(listeners, fn)
+}
+
+// Nomor generasi data, beda generasi berarti hasil pencarian bisa beda
+func Generation() uint64 {
+	return generation.Load()
+}
+
+func notifyChange() {
+	generation.Add(1)
+	listenerMu.Lock()
+	fns := append([]func(){}, listeners...)
+	listenerMu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// Ambil dataset berdasarkan nama, kosong berarti dataset default
+func GetDataset(name string) (*Dataset, bool) {
+	if name == "" {
+		name = DefaultDataset
+	}
+
+	datasetsMu.RLock()
+	defer datasetsMu.RUnlock()
+	d, exists := datasets[strings.ToLower(name)]
+	return d, exists
+}
+
+func Default() *Dataset {
+	d, _ := GetDataset(DefaultDataset)
+	return d
+}
+
+func ListDatasets() []*Dataset {
+	datasetsMu.RLock()
+	defer datasetsMu.RUnlock()
+
+	var result []*Dataset
+	for _, d := range datasets {
+		result = append(result, d)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func LoadDatasetFromFile(filename string) (*Dataset, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var file datasetFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if file.Name == "" {
+		file.Name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	if len(file.BaseComponents) == 0 {
+		return nil, fmt.Errorf("dataset %s tidak punya base_components", file.Name)
+	}
+
+	return NewDataset(file.Name, file.Version, file.Elements, file.BaseComponents), nil
+}
+
+// Load semua dataset tambahan (*.json) dari satu folder
+func LoadDatasetsFromDir(dir string) ([]*Dataset, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var loaded []*Dataset
+	for _, file := range files {
+		d, err := LoadDatasetFromFile(file)
+		if err != nil {
+			return loaded, fmt.Errorf("%s: %w", file, err)
+		}
+		RegisterDataset(d)
+		loaded = append(loaded, d)
+	}
+	return loaded, nil
+}
@@ -0,0 +1,153 @@
+package Element
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+type TierChange struct {
+	Element string `json:"element"`
+	OldTier string `json:"old_tier"`
+	NewTier string `json:"new_tier"`
+}
+
+// Perbedaan dataset hasil scrape baru dengan dataset sebelumnya
+type DatasetDiff struct {
+	GeneratedAt     time.Time    `json:"generated_at"`
+	AddedElements   []string     `json:"added_elements"`
+	RemovedElements []string     `json:"removed_elements"`
+	AddedRecipes    []Element    `json:"added_recipes"`
+	RemovedRecipes  []Element    `json:"removed_recipes"`
+	TierChanges     []TierChange `json:"tier_changes"`
+}
+
+func (d DatasetDiff) IsEmpty() bool {
+	return len(d.AddedElements) == 0 &&
+		len(d.RemovedElements) == 0 &&
+		len(d.AddedRecipes) == 0 &&
+		len(d.RemovedRecipes) == 0 &&
+		len(d.TierChanges) == 0
+}
+
+// Key resep, urutan Left/Right ga ngaruh (Water + Fire == Fire + Water)
+func recipeKey(e Element) string {
+	left := strings.ToLower(e.Left)
+	right := strings.ToLower(e.Right)
+	if left > right {
+		left, right = right, left
+	}
+	return strings.ToLower(e.Root) + "|" + left + "|" + right
+}
+
+func isRecipe(e Element) bool {
+	return e.Left != "" && e.Right != ""
+}
+
+// Ambil tier + nama asli tiap elemen (key lowercase)
+func elementTiers(elements []Element) (map[string]string, map[string]string) {
+	tiers := make(map[string]string)
+	names := make(map[string]string)
+	for _, e := range elements {
+		key := strings.ToLower(e.Root)
+		if _, ok := names[key]; ok {
+			continue
+		}
+		names[key] = e.Root
+		tiers[key] = e.Tier
+	}
+	return tiers, names
+}
+
+func recipeSet(elements []Element) map[string]Element {
+	set := make(map[string]Element)
+	for _, e := range elements {
+		if isRecipe(e) {
+			set[recipeKey(e)] = e
+		}
+	}
+	return set
+}
+
+// Bandingin dataset lama dan baru
+func DiffElements(oldElements, newElements []Element) DatasetDiff {
+	diff := DatasetDiff{
+		GeneratedAt:     time.Now().UTC(),
+		AddedElements:   []string{},
+		RemovedElements: []string{},
+		AddedRecipes:    []Element{},
+		RemovedRecipes:  []Element{},
+		TierChanges:     []TierChange{},
+	}
+
+	oldTiers, oldNames := elementTiers(oldElements)
+	newTiers, newNames := elementTiers(newElements)
+
+	for key, name := range newNames {
+		oldTier, exists := oldTiers[key]
+		if !exists {
+			diff.AddedElements = append(diff.AddedElements, name)
+			continue
+		}
+		if oldTier != newTiers[key] {
+			diff.TierChanges = append(diff.TierChanges, TierChange{
+				Element: name,
+				OldTier: oldTier,
+				NewTier: newTiers[key],
+			})
+		}
+	}
+	for key, name := range oldNames {
+		if _, exists := newNames[key]; !exists {
+			diff.RemovedElements = append(diff.RemovedElements, name)
+		}
+	}
+
+	oldRecipes := recipeSet(oldElements)
+	newRecipes := recipeSet(newElements)
+	for key, recipe := range newRecipes {
+		if _, exists := oldRecipes[key]; !exists {
+			diff.AddedRecipes = append(diff.AddedRecipes, recipe)
+		}
+	}
+	for key, recipe := range oldRecipes {
+		if _, exists := newRecipes[key]; !exists {
+			diff.RemovedRecipes = append(diff.RemovedRecipes, recipe)
+		}
+	}
+
+	// Urutin biar output stabil
+	sort.Strings(diff.AddedElements)
+	sort.Strings(diff.RemovedElements)
+	sort.Slice(diff.AddedRecipes, func(i, j int) bool {
+		return recipeKey(diff.AddedRecipes[i]) < recipeKey(diff.AddedRecipes[j])
+	})
+	sort.Slice(diff.RemovedRecipes, func(i, j int) bool {
+		return recipeKey(diff.RemovedRecipes[i]) < recipeKey(diff.RemovedRecipes[j])
+	})
+	sort.Slice(diff.TierChanges, func(i, j int) bool {
+		return diff.TierChanges[i].Element < diff.TierChanges[j].Element
+	})
+
+	return diff
+}
+
+func SaveDiffToFile(diff DatasetDiff, filename string) error {
+	data, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(filename, data, 0644)
+}
+
+func LoadDiffFromFile(filename string) (DatasetDiff, error) {
+	var diff DatasetDiff
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return diff, err
+	}
+	err = json.Unmarshal(data, &diff)
+	return diff, err
+}
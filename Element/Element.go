@@ -1,9 +1,9 @@
 package Element
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"os"
 	"strings"
 )
@@ -15,10 +15,21 @@ type Element struct {
 	Tier  string `json:"Tier"`
 }
 
-var allElements []Element
-
+// Elemen dataset default
 func GetAllElement() []Element {
-	return allElements
+	return Default().Elements
+}
+
+// Ganti isi dataset default yang lagi aktif
+func SetElements(elements []Element, version string) {
+	RegisterDataset(NewDataset(DefaultDataset, version, elements, BaseComponents))
+}
+
+// Ganti base components dataset default (dari config), isi dataset tetap
+func SetBaseComponents(base map[string]bool) {
+	BaseComponents = base
+	current := Default()
+	RegisterDataset(NewDataset(DefaultDataset, current.Version, current.Elements, BaseComponents))
 }
 
 func LoadElementsFromFile(filename string) error {
@@ -27,29 +38,18 @@ func LoadElementsFromFile(filename string) error {
 		return err
 	}
 
-	err = json.Unmarshal(data, &allElements)
+	var elements []Element
+	err = json.Unmarshal(data, &elements)
 	if err != nil {
 		return err
 	}
+	SetElements(elements, "")
 	return nil
 }
 
+// Helper lama tanpa request, log-nya jatuh ke logger default
 func GetElements(rootName string) []Element {
-	var result []Element
-	for _, elem := range allElements {
-		if strings.EqualFold(elem.Root, rootName) {
-			result = append(result, elem)
-		}
-	}
-
-	// Debugging log
-	if len(result) == 0 {
-		log.Printf("No elements found with root name: %s", rootName)
-	} else {
-		log.Printf("Found %d elements for root name: %s", len(result), rootName)
-	}
-
-	return result
+	return Default().GetElements(context.Background(), rootName)
 }
 
 func (e Element) LeftChildren() []Element {
@@ -60,7 +60,7 @@ func (e Element) RightChildren() []Element {
 	return GetElements(e.Right)
 }
 
-// Define base components, jika 'time' ga masuk
+// Base components default (LA2), jika 'time' ga masuk
 var BaseComponents = map[string]bool{
 	"air":   true,
 	"earth": true,
@@ -78,24 +78,14 @@ func BuildRecipeMap(recipes []Element) map[string][]Element {
 	return recipeMap
 }
 
-// Cek apakah base component
+// Cek apakah base component di dataset default
 func IsBaseComponent(item string) bool {
-	return BaseComponents[strings.ToLower(item)]
+	return Default().IsBaseComponent(item)
 }
 
-// Validasi bahwa leaf node adalah base component
+// Validasi bahwa leaf node adalah base component dataset default
 func ValidateTree(tree Tree) bool {
-	if len(tree.Children) == 0 {
-		return IsBaseComponent(tree.Root.Root)
-	}
-
-	for _, child := range tree.Children {
-		if !ValidateTree(child) {
-			return false
-		}
-	}
-
-	return true
+	return Default().ValidateTree(tree)
 }
 
 func ParseTier(tierStr string) int {
@@ -0,0 +1,122 @@
+package Element
+
+import (
+	"sort"
+	"strings"
+)
+
+// Ringkasan isi dataset
+type DatasetStats struct {
+	Elements       int         `json:"elements"`
+	Recipes        int         `json:"recipes"`
+	BaseComponents int         `json:"base_components"`
+	MaxTier        int         `json:"max_tier"`
+	ElementsByTier map[int]int `json:"elements_by_tier"`
+}
+
+// Nama asli elemen (sesuai dataset), kosong kalau ga ada
+func (d *Dataset) DisplayName(name string) string {
+	return d.names[strings.ToLower(strings.TrimSpace(name))]
+}
+
+func (d *Dataset) Tier(name string) (int, bool) {
+	recipes, exists := d.RecipeMap[strings.ToLower(strings.TrimSpace(name))]
+	if !exists || len(recipes) == 0 {
+		return 0, false
+	}
+	return ParseTier(recipes[0].Tier), true
+}
+
+// Resep yang memakai elemen ini sebagai bahan
+func (d *Dataset) UsedIn(name string) []Element {
+	key := strings.ToLower(strings.TrimSpace(name))
+	var result []Element
+	for _, e := range d.Elements {
+		if strings.EqualFold(e.Left, key) || strings.EqualFold(e.Right, key) {
+			result = append(result, e)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return recipeKey(result[i]) < recipeKey(result[j])
+	})
+	return result
+}
+
+// Rantai resep terpendek dari a ke b: tiap langkah memakai hasil langkah sebelumnya sebagai bahan
+func (d *Dataset) Path(from, to string) ([]Element, bool) {
+	start := strings.ToLower(strings.TrimSpace(from))
+	target := strings.ToLower(strings.TrimSpace(to))
+	if start == target {
+		return []Element{}, true
+	}
+
+	// Index bahan -> resep yang memakainya
+	usedIn := make(map[string][]Element)
+	for _, e := range d.Elements {
+		if e.Left == "" || e.Right == "" {
+			continue
+		}
+		left := strings.ToLower(e.Left)
+		right := strings.ToLower(e.Right)
+		usedIn[left] = append(usedIn[left], e)
+		if right != left {
+			usedIn[right] = append(usedIn[right], e)
+		}
+	}
+
+	// Simpan resep + bahan asal tiap elemen buat nyusun jalur balik
+	type edge struct {
+		recipe Element
+		from   string
+	}
+	prev := make(map[string]edge)
+	visited := map[string]bool{start: true}
+	queue := []string{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, recipe := range usedIn[current] {
+			next := strings.ToLower(recipe.Root)
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			prev[next] = edge{recipe, current}
+
+			if next == target {
+				var path []Element
+				for node := target; node != start; node = prev[node].from {
+					path = append([]Element{prev[node].recipe}, path...)
+				}
+				return path, true
+			}
+			queue = append(queue, next)
+		}
+	}
+	return nil, false
+}
+
+func (d *Dataset) Stats() DatasetStats {
+	stats := DatasetStats{ElementsByTier: make(map[int]int)}
+	for _, recipes := range d.RecipeMap {
+		stats.Elements++
+		tier := ParseTier(recipes[0].Tier)
+		stats.ElementsByTier[tier]++
+		if tier > stats.MaxTier {
+			stats.MaxTier = tier
+		}
+		for _, r := range recipes {
+			if r.Left != "" && r.Right != "" {
+				stats.Recipes++
+			}
+		}
+	}
+	for _, isBase := range d.BaseComponents {
+		if isBase {
+			stats.BaseComponents++
+		}
+	}
+	return stats
+}
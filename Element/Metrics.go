@@ -0,0 +1,170 @@
+package Element
+
+import (
+	"context"
+	"errors"
+	"runtime/metrics"
+	"sync/atomic"
+	"time"
+)
+
+// Pencarian dihentikan karena budget node bareng (/batch) udah habis
+var ErrBudgetExhausted = errors.New("budget node habis")
+
+// Context cuma dicek tiap sekian node biar Visit tetap murah
+const cancelCheckInterval = 256
+
+// Metrics hasil pencarian, dipake bareng sama BFS dan DFS
+type MetricsResult struct {
+	NodesVisited  int64  `json:"nodes_visited"`
+	Duration      int64  `json:"duration_ms"`
+	DurationHuman string `json:"duration_human"`
+	// Kedalaman tree resep terdalam yang dijelajahi
+	MaxDepth int64 `json:"max_depth"`
+	// Ukuran frontier terbesar (queue BFS / call yang lagi jalan di DFS)
+	PeakFrontier    int64 `json:"peak_frontier"`
+	PrunedByTier    int64 `json:"pruned_by_tier"`
+	PrunedByVisited int64 `json:"pruned_by_visited"`
+	// Cabang yang dipangkas karena exclude/require/max_depth/max_nodes
+	PrunedByConstraint int64 `json:"pruned_by_constraint"`
+	GoroutinesSpawned  int64 `json:"goroutines_spawned"`
+	// Perkiraan, diambil dari selisih total alokasi heap runtime (ikut kehitung alokasi request lain)
+	BytesAllocated uint64 `json:"bytes_allocated"`
+	// Diisi handler, versi snapshot dataset yang dipake
+	DatasetVersion string `json:"dataset_version,omitempty"`
+}
+
+// Pencatat metrics selama pencarian, aman dipake dari banyak goroutine
+type SearchStats struct {
+	start      time.Time
+	allocStart uint64
+
+	nodes              atomic.Int64
+	maxDepth           atomic.Int64
+	frontier           atomic.Int64
+	peakFrontier       atomic.Int64
+	prunedByTier       atomic.Int64
+	prunedByVisited    atomic.Int64
+	prunedByConstraint atomic.Int64
+	goroutines         atomic.Int64
+
+	// Batas dari luar, cuma diisi NewBudgetedSearchStats
+	ctx    context.Context
+	used   *atomic.Int64
+	budget int64
+	err    atomic.Pointer[error]
+}
+
+// Sama dengan MemStats.TotalAlloc tapi ga stop-the-world kayak runtime.ReadMemStats,
+// jadi aman dipanggil di awal dan akhir tiap pencarian (termasuk ratusan query /batch)
+func totalAlloc() uint64 {
+	sample := []metrics.Sample{{Name: "/gc/heap/allocs:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+func NewSearchStats() *SearchStats {
+	return &SearchStats{start: time.Now(), allocStart: totalAlloc()}
+}
+
+// SearchStats yang ikut ngitung ke counter node bareng used. Pencarian berhenti
+// kalau used lewat budget (0 = tanpa batas) atau ctx dibatalkan, cek lewat Stopped
+func NewBudgetedSearchStats(ctx context.Context, used *atomic.Int64, budget int64) *SearchStats {
+	s := NewSearchStats()
+	s.ctx = ctx
+	s.used = used
+	s.budget = budget
+	return s
+}
+
+func storeMax(v *atomic.Int64, n int64) {
+	for {
+		current := v.Load()
+		if n <= current || v.CompareAndSwap(current, n) {
+			return
+		}
+	}
+}
+
+// Catat satu node yang beneran dijelajahi di kedalaman depth
+func (s *SearchStats) Visit(depth int) {
+	n := s.nodes.Add(1)
+	storeMax(&s.maxDepth, int64(depth))
+
+	if s.used != nil && s.used.Add(1) > s.budget && s.budget > 0 {
+		s.stop(ErrBudgetExhausted)
+	}
+	if s.ctx != nil && n%cancelCheckInterval == 0 && s.ctx.Err() != nil {
+		s.stop(s.ctx.Err())
+	}
+}
+
+func (s *SearchStats) stop(err error) {
+	s.err.CompareAndSwap(nil, &err)
+}
+
+// Pencarian harus berhenti, hasil yang udah ketemu tetap valid tapi belum lengkap
+func (s *SearchStats) Stopped() bool {
+	return s.err.Load() != nil
+}
+
+// Alasan pencarian dihentikan (ErrBudgetExhausted atau error context), nil kalau selesai normal
+func (s *SearchStats) Err() error {
+	if err := s.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// Frontier nambah/kurang n
+func (s *SearchStats) Push(n int) {
+	storeMax(&s.peakFrontier, s.frontier.Add(int64(n)))
+}
+
+func (s *SearchStats) Pop(n int) {
+	s.frontier.Add(-int64(n))
+}
+
+func (s *SearchStats) PruneTier() {
+	s.prunedByTier.Add(1)
+}
+
+func (s *SearchStats) PruneVisited() {
+	s.prunedByVisited.Add(1)
+}
+
+func (s *SearchStats) PruneConstraint() {
+	s.prunedByConstraint.Add(1)
+}
+
+func (s *SearchStats) Spawn(n int) {
+	s.goroutines.Add(int64(n))
+}
+
+func (s *SearchStats) NodesVisited() int64 {
+	return s.nodes.Load()
+}
+
+// Rangkum jadi MetricsResult, dipanggil setelah pencarian selesai
+func (s *SearchStats) Result() MetricsResult {
+	duration := time.Since(s.start)
+	var allocated uint64
+	if end := totalAlloc(); end > s.allocStart {
+		allocated = end - s.allocStart
+	}
+	return MetricsResult{
+		NodesVisited:       s.nodes.Load(),
+		Duration:           duration.Milliseconds(),
+		DurationHuman:      duration.String(),
+		MaxDepth:           s.maxDepth.Load(),
+		PeakFrontier:       s.peakFrontier.Load(),
+		PrunedByTier:       s.prunedByTier.Load(),
+		PrunedByVisited:    s.prunedByVisited.Load(),
+		PrunedByConstraint: s.prunedByConstraint.Load(),
+		GoroutinesSpawned:  s.goroutines.Load(),
+		BytesAllocated:     allocated,
+	}
+}
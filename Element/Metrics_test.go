@@ -0,0 +1,59 @@
+package Element
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBudgetedStatsStopsAtBudget(t *testing.T) {
+	var used atomic.Int64
+	s := NewBudgetedSearchStats(context.Background(), &used, 3)
+	for i := 0; i < 3; i++ {
+		s.Visit(0)
+	}
+	if s.Stopped() {
+		t.Fatal("belum lewat budget tapi udah berhenti")
+	}
+	s.Visit(0)
+	if !s.Stopped() || !errors.Is(s.Err(), ErrBudgetExhausted) {
+		t.Errorf("Stopped = %v, Err = %v, mau berhenti dengan ErrBudgetExhausted", s.Stopped(), s.Err())
+	}
+
+	// Budget dipakai bareng, stats lain langsung ikut habis
+	other := NewBudgetedSearchStats(context.Background(), &used, 3)
+	other.Visit(0)
+	if !other.Stopped() {
+		t.Error("stats kedua harus berhenti karena budget bareng udah habis")
+	}
+}
+
+func TestBudgetedStatsCancelled(t *testing.T) {
+	var used atomic.Int64
+	ctx, cancel := context.WithCancel(context.Background())
+	s := NewBudgetedSearchStats(ctx, &used, 0)
+	cancel()
+	for i := 0; i < cancelCheckInterval; i++ {
+		s.Visit(0)
+	}
+	if !errors.Is(s.Err(), context.Canceled) {
+		t.Errorf("Err = %v, mau context.Canceled", s.Err())
+	}
+	if used.Load() != cancelCheckInterval {
+		t.Errorf("used = %d, mau %d", used.Load(), cancelCheckInterval)
+	}
+}
+
+func TestSearchStatsUnlimited(t *testing.T) {
+	s := NewSearchStats()
+	for i := 0; i < 1000; i++ {
+		s.Visit(i % 5)
+	}
+	if s.Stopped() || s.Err() != nil {
+		t.Error("stats tanpa budget ga boleh berhenti")
+	}
+	if result := s.Result(); result.NodesVisited != 1000 || result.MaxDepth != 4 {
+		t.Errorf("result = %+v", result)
+	}
+}
@@ -0,0 +1,246 @@
+package Element
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Folder penyimpanan pack yang di-upload, bisa diganti lewat config
+var PackDir = "data/packs"
+
+// Recipe pack buatan user, ditimpa di atas dataset hasil scrape
+type Pack struct {
+	Name string `json:"name"`
+	// Dataset tempat pack divalidasi waktu upload, cuma boleh ditimpa ke dataset ini
+	Dataset    string    `json:"dataset,omitempty"`
+	UploadedAt time.Time `json:"uploaded_at"`
+	Elements   []Element `json:"elements"`
+}
+
+var (
+	packs   = make(map[string]*Pack)
+	packsMu sync.RWMutex
+)
+
+// Hasil Overlay per dataset dasar dan urutan pack, dibuang tiap generation berubah
+type overlayKey struct {
+	base  *Dataset
+	packs string
+}
+
+const maxOverlayCache = 32
+
+var (
+	overlayCache    = make(map[overlayKey]*Dataset)
+	overlayCacheGen uint64
+	overlayCacheMu  sync.Mutex
+)
+
+// Dataset dasar pack, pack lama yang disimpan sebelum ada field Dataset dianggap milik la2
+func (p *Pack) BaseDataset() string {
+	if p.Dataset == "" {
+		return DefaultDataset
+	}
+	return p.Dataset
+}
+
+// Cek format pack, bahan resep harus ada di dataset dasar atau di pack itu sendiri.
+// overrides berisi resep dataset dasar yang tier-nya diganti pack (bukan error)
+func ValidatePack(base *Dataset, elements []Element) (problems []string, overrides []string) {
+	existing := make(map[string]Element)
+	for _, e := range base.Elements {
+		existing[recipeKey(e)] = e
+	}
+
+	known := make(map[string]bool)
+	for _, e := range base.Elements {
+		known[strings.ToLower(e.Root)] = true
+	}
+	for k := range base.BaseComponents {
+		known[k] = true
+	}
+	for _, e := range elements {
+		if strings.TrimSpace(e.Root) != "" {
+			known[strings.ToLower(e.Root)] = true
+		}
+	}
+
+	for i, e := range elements {
+		if strings.TrimSpace(e.Root) == "" {
+			problems = append(problems, fmt.Sprintf("elemen #%d: root kosong", i))
+			continue
+		}
+		if (e.Left == "") != (e.Right == "") {
+			problems = append(problems, fmt.Sprintf("elemen #%d (%s): Left dan Right harus diisi dua-duanya atau kosong dua-duanya", i, e.Root))
+		}
+		var tier int
+		if _, err := fmt.Sscanf(e.Tier, "%d", &tier); err != nil || tier < 0 {
+			problems = append(problems, fmt.Sprintf("elemen #%d (%s): tier %q tidak valid", i, e.Root, e.Tier))
+		}
+		for _, ingredient := range []string{e.Left, e.Right} {
+			if ingredient != "" && !known[strings.ToLower(ingredient)] {
+				problems = append(problems, fmt.Sprintf("elemen #%d (%s): bahan %q tidak dikenal", i, e.Root, ingredient))
+			}
+		}
+		if old, exists := existing[recipeKey(e)]; exists && old.Tier != e.Tier {
+			overrides = append(overrides, fmt.Sprintf("%s (tier %s -> %s)", recipeKey(e), old.Tier, e.Tier))
+		}
+	}
+
+	return problems, overrides
+}
+
+func SavePack(p *Pack) {
+	packsMu.Lock()
+	packs[strings.ToLower(p.Name)] = p
+	packsMu.Unlock()
+	notifyChange()
+}
+
+func GetPack(name string) (*Pack, bool) {
+	packsMu.RLock()
+	defer packsMu.RUnlock()
+	p, exists := packs[strings.ToLower(name)]
+	return p, exists
+}
+
+func DeletePack(name string) bool {
+	packsMu.Lock()
+	_, exists := packs[strings.ToLower(name)]
+	delete(packs, strings.ToLower(name))
+	packsMu.Unlock()
+	if exists {
+		notifyChange()
+	}
+	return exists
+}
+
+func ListPacks() []*Pack {
+	packsMu.RLock()
+	defer packsMu.RUnlock()
+
+	var result []*Pack
+	for _, p := range packs {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Gabungin dataset dasar dengan pack, resep yang sama ga dobel.
+// Resep pack menimpa resep dengan root dan bahan yang sama (misalnya ganti tier)
+// di posisi aslinya, pack yang belakangan menimpa pack sebelumnya.
+// Hasilnya di-cache sampai dataset atau pack berubah, jadi recipe map dan index
+// ga dibangun ulang tiap request
+func Overlay(base *Dataset, overlays ...*Pack) *Dataset {
+	if len(overlays) == 0 {
+		return base
+	}
+
+	var names []string
+	for _, p := range overlays {
+		names = append(names, p.Name)
+	}
+	key := overlayKey{base: base, packs: strings.ToLower(strings.Join(names, "+"))}
+	gen := Generation()
+
+	overlayCacheMu.Lock()
+	if overlayCacheGen != gen {
+		overlayCache = make(map[overlayKey]*Dataset)
+		overlayCacheGen = gen
+	}
+	ds, exists := overlayCache[key]
+	overlayCacheMu.Unlock()
+	if exists {
+		return ds
+	}
+
+	ds = overlay(base, overlays, names)
+
+	overlayCacheMu.Lock()
+	if overlayCacheGen == gen {
+		if len(overlayCache) >= maxOverlayCache {
+			overlayCache = make(map[overlayKey]*Dataset)
+		}
+		overlayCache[key] = ds
+	}
+	overlayCacheMu.Unlock()
+	return ds
+}
+
+func overlay(base *Dataset, overlays []*Pack, names []string) *Dataset {
+	index := make(map[string]int)
+	var merged []Element
+	add := func(e Element) {
+		key := recipeKey(e)
+		if i, exists := index[key]; exists {
+			merged[i] = e
+			return
+		}
+		index[key] = len(merged)
+		merged = append(merged, e)
+	}
+
+	for _, e := range base.Elements {
+		add(e)
+	}
+
+	for _, p := range overlays {
+		for _, e := range p.Elements {
+			add(e)
+		}
+	}
+
+	version := base.Version + "+" + strings.Join(names, "+")
+	return NewDataset(base.Name, version, merged, base.BaseComponents)
+}
+
+func SavePackToFile(p *Pack, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(filepath.Join(dir, strings.ToLower(p.Name)+".json"), data, 0644)
+}
+
+// Load pack yang pernah di-upload, dipanggil waktu server start
+func LoadPacksFromDir(dir string) ([]*Pack, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var loaded []*Pack
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return loaded, err
+		}
+		var p Pack
+		if err := json.Unmarshal(data, &p); err != nil {
+			return loaded, fmt.Errorf("%s: %w", file, err)
+		}
+		SavePack(&p)
+		loaded = append(loaded, &p)
+	}
+	return loaded, nil
+}
+
+func RemovePackFile(name string, dir string) error {
+	err := os.Remove(filepath.Join(dir, strings.ToLower(name)+".json"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
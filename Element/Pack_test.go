@@ -0,0 +1,103 @@
+package Element
+
+import (
+	"reflect"
+	"testing"
+)
+
+func packBase() *Dataset {
+	return NewDataset("test", "1", []Element{
+		{Root: "Air", Tier: "0"},
+		{Root: "Earth", Tier: "0"},
+		{Root: "Fire", Tier: "0"},
+		{Root: "Water", Tier: "0"},
+		{Root: "Steam", Left: "Water", Right: "Fire", Tier: "1"},
+		{Root: "Mud", Left: "Water", Right: "Earth", Tier: "1"},
+	}, BaseComponents)
+}
+
+func TestOverlayOverridesBase(t *testing.T) {
+	pack := &Pack{Name: "mod", Elements: []Element{
+		// Urutan bahan dibalik tapi tetap resep yang sama
+		{Root: "Steam", Left: "Fire", Right: "Water", Tier: "3"},
+		{Root: "Lava", Left: "Earth", Right: "Fire", Tier: "1"},
+	}}
+	ds := Overlay(packBase(), pack)
+
+	if ds.Version != "1+mod" {
+		t.Errorf("versi = %q, mau 1+mod", ds.Version)
+	}
+	steam := ds.RecipeMap["steam"]
+	if len(steam) != 1 || steam[0].Tier != "3" {
+		t.Errorf("resep steam = %+v, mau satu resep tier 3 dari pack", steam)
+	}
+	if len(ds.RecipeMap["lava"]) != 1 {
+		t.Errorf("resep lava dari pack ga ikut: %+v", ds.RecipeMap["lava"])
+	}
+	if len(ds.Elements) != 7 {
+		t.Errorf("jumlah elemen = %d, mau 7", len(ds.Elements))
+	}
+	// Resep yang ditimpa tetap di posisi aslinya
+	if ds.Elements[4].Root != "Steam" || ds.Elements[4].Tier != "3" {
+		t.Errorf("elemen #4 = %+v, mau Steam tier 3", ds.Elements[4])
+	}
+}
+
+func TestOverlayLaterPackWins(t *testing.T) {
+	first := &Pack{Name: "a", Elements: []Element{{Root: "Steam", Left: "Water", Right: "Fire", Tier: "2"}}}
+	second := &Pack{Name: "b", Elements: []Element{{Root: "Steam", Left: "Water", Right: "Fire", Tier: "5"}}}
+	ds := Overlay(packBase(), first, second)
+	if steam := ds.RecipeMap["steam"]; len(steam) != 1 || steam[0].Tier != "5" {
+		t.Errorf("resep steam = %+v, mau tier 5 dari pack terakhir", steam)
+	}
+}
+
+func TestOverlayWithoutPacks(t *testing.T) {
+	base := packBase()
+	if Overlay(base) != base {
+		t.Error("Overlay tanpa pack harus balikin dataset dasar")
+	}
+}
+
+func TestValidatePack(t *testing.T) {
+	problems, overrides := ValidatePack(packBase(), []Element{
+		{Root: "Steam", Left: "Fire", Right: "Water", Tier: "3"},
+		{Root: "Mud", Left: "Water", Right: "Earth", Tier: "1"},
+		{Root: "Golem", Left: "Mud", Right: "Unknown", Tier: "2"},
+		{Root: "", Tier: "1"},
+	})
+	if len(problems) != 2 {
+		t.Errorf("problems = %v, mau 2 (bahan tidak dikenal, root kosong)", problems)
+	}
+	// Mud sama persis, jadi bukan override
+	if want := []string{"steam|fire|water (tier 1 -> 3)"}; !reflect.DeepEqual(overrides, want) {
+		t.Errorf("overrides = %v, mau %v", overrides, want)
+	}
+}
+
+func TestOverlayCached(t *testing.T) {
+	base := packBase()
+	pack := &Pack{Name: "mod", Elements: []Element{{Root: "Lava", Left: "Earth", Right: "Fire", Tier: "1"}}}
+
+	first := Overlay(base, pack)
+	if Overlay(base, pack) != first {
+		t.Error("Overlay kedua dengan dataset dan pack yang sama harus pakai hasil cache")
+	}
+	if Overlay(packBase(), pack) == first {
+		t.Error("dataset dasar beda ga boleh pakai cache yang sama")
+	}
+
+	notifyChange()
+	if Overlay(base, pack) == first {
+		t.Error("cache harus dibuang setelah dataset atau pack berubah")
+	}
+}
+
+func TestPackBaseDataset(t *testing.T) {
+	if got := (&Pack{Name: "lama"}).BaseDataset(); got != DefaultDataset {
+		t.Errorf("BaseDataset pack lama = %q, mau %q", got, DefaultDataset)
+	}
+	if got := (&Pack{Name: "baru", Dataset: "la1"}).BaseDataset(); got != "la1" {
+		t.Errorf("BaseDataset = %q, mau la1", got)
+	}
+}
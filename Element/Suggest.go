@@ -0,0 +1,201 @@
+package Element
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Jenis kecocokan, urutan = prioritas ranking
+const (
+	MatchExact = iota
+	MatchPrefix
+	MatchWordPrefix
+	MatchSubstring
+	MatchFuzzy
+)
+
+var matchNames = []string{"exact", "prefix", "word_prefix", "substring", "fuzzy"}
+
+// Satu entri index pencarian elemen
+type indexEntry struct {
+	name       string
+	key        string
+	normalized string
+	words      []string
+	tier       int
+	recipes    int
+}
+
+// Hasil pencarian elemen
+type ElementMatch struct {
+	Name     string `json:"name"`
+	Tier     int    `json:"tier"`
+	Recipes  int    `json:"recipes"`
+	Match    string `json:"match"`
+	Distance int    `json:"distance"`
+
+	rank int
+}
+
+// Buang spasi dan tanda baca: "Philosopher's Stone" -> "philosophersstone"
+func normalizeName(s string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// Index dibangun sekali per dataset, jadi ikut ke-reload tiap snapshot/scrape baru
+func buildSearchIndex(elements []Element) []indexEntry {
+	byKey := make(map[string]*indexEntry)
+	var order []string
+	for _, e := range elements {
+		key := strings.ToLower(e.Root)
+		if key == "" {
+			continue
+		}
+		entry, exists := byKey[key]
+		if !exists {
+			entry = &indexEntry{
+				name:       e.Root,
+				key:        key,
+				normalized: normalizeName(e.Root),
+				words:      strings.Fields(key),
+				tier:       ParseTier(e.Tier),
+			}
+			byKey[key] = entry
+			order = append(order, key)
+		}
+		if e.Left != "" && e.Right != "" {
+			entry.recipes++
+		}
+	}
+
+	index := make([]indexEntry, 0, len(order))
+	for _, key := range order {
+		index = append(index, *byKey[key])
+	}
+	return index
+}
+
+func matchEntry(entry indexEntry, query string, normalized string) (int, int, bool) {
+	switch {
+	case entry.key == query || (normalized != "" && entry.normalized == normalized):
+		return MatchExact, 0, true
+	case strings.HasPrefix(entry.key, query) || (normalized != "" && strings.HasPrefix(entry.normalized, normalized)):
+		return MatchPrefix, 0, true
+	}
+	for _, word := range entry.words[min(1, len(entry.words)):] {
+		if strings.HasPrefix(word, query) {
+			return MatchWordPrefix, 0, true
+		}
+	}
+	if strings.Contains(entry.key, query) || (normalized != "" && strings.Contains(entry.normalized, normalized)) {
+		return MatchSubstring, 0, true
+	}
+
+	distance := Levenshtein(normalized, entry.normalized)
+	if distance <= len(normalized)/3+1 {
+		return MatchFuzzy, distance, true
+	}
+	return 0, 0, false
+}
+
+// Cari elemen: prefix dulu, lalu awal kata, substring, terakhir edit distance
+func (d *Dataset) SearchElements(query string, limit int) []ElementMatch {
+	query = strings.ToLower(strings.TrimSpace(query))
+	normalized := normalizeName(query)
+
+	result := []ElementMatch{}
+	if query == "" {
+		return result
+	}
+
+	for _, entry := range d.index {
+		rank, distance, ok := matchEntry(entry, query, normalized)
+		if !ok {
+			continue
+		}
+		result = append(result, ElementMatch{
+			Name:     entry.name,
+			Tier:     entry.tier,
+			Recipes:  entry.recipes,
+			Match:    matchNames[rank],
+			Distance: distance,
+			rank:     rank,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if len(a.Name) != len(b.Name) {
+			return len(a.Name) < len(b.Name)
+		}
+		return a.Name < b.Name
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// Edit distance Levenshtein (case-insensitive)
+func Levenshtein(a, b string) int {
+	ra := []rune(strings.ToLower(a))
+	rb := []rune(strings.ToLower(b))
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Cek elemen ada di dataset tanpa log
+func (d *Dataset) HasElement(name string) bool {
+	_, exists := d.names[strings.ToLower(strings.TrimSpace(name))]
+	return exists
+}
+
+// Nama asli semua elemen di dataset, urut alfabet
+func (d *Dataset) ElementNames() []string {
+	var result []string
+	for _, name := range d.names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Nama elemen yang mirip, buat saran kalau user salah ketik
+func (d *Dataset) Suggest(name string, limit int) []string {
+	result := []string{}
+	for _, match := range d.SearchElements(name, limit) {
+		result = append(result, match.Name)
+	}
+	return result
+}
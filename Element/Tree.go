@@ -1,7 +1,6 @@
 package Element
 
 import (
-	"fmt"
 	"strconv"
 )
 
@@ -28,6 +27,15 @@ func (t Tree) GetChildren() []Tree {
 	return t.Children
 }
 
+// Jumlah node tree
+func (t Tree) Size() int {
+	size := 1
+	for _, child := range t.Children {
+		size += child.Size()
+	}
+	return size
+}
+
 func (t Tree) GetTier() int {
 	i, _ := strconv.Atoi(t.Root.Tier)
 	return i
@@ -132,11 +140,9 @@ func AreAllTreesUnique(trees []Tree) bool {
 	for i := 0; i < len(trees); i++ {
 		for j := i + 1; j < len(trees); j++ {
 			if TreesEqual(trees[i], trees[j]) {
-				fmt.Println("not unique")
 				return false
 			}
 		}
 	}
-	fmt.Println("unique recipe")
 	return true
 }
@@ -0,0 +1,53 @@
+// Package elementtest berisi dataset dan helper tree kecil yang dipakai bareng
+// oleh test Element, BFS, DFS dan Handler
+package elementtest
+
+import (
+	"stima-2-be/Element"
+	"strings"
+)
+
+// T = A + B, A = Steam + Earth | Air + Earth, B = Fire + Air, U = D + C.
+// Resep pertama tiap elemen sengaja yang ga memenuhi constraints,
+// biar ketahuan kalau subtree dipotong limit sebelum constraints dicek
+func ConstraintsDataset() *Element.Dataset {
+	return Element.NewDataset("test", "1", []Element.Element{
+		{Root: "Air", Tier: "0"},
+		{Root: "Earth", Tier: "0"},
+		{Root: "Fire", Tier: "0"},
+		{Root: "Water", Tier: "0"},
+		{Root: "Steam", Left: "Water", Right: "Fire", Tier: "1"},
+		{Root: "A", Left: "Steam", Right: "Earth", Tier: "2"},
+		{Root: "A", Left: "Air", Right: "Earth", Tier: "2"},
+		{Root: "B", Left: "Fire", Right: "Air", Tier: "1"},
+		{Root: "T", Left: "A", Right: "B", Tier: "3"},
+		{Root: "C", Left: "Air", Right: "Earth", Tier: "2"},
+		{Root: "C", Left: "Steam", Right: "Air", Tier: "2"},
+		{Root: "D", Left: "Air", Right: "Earth", Tier: "2"},
+		{Root: "D", Left: "Steam", Right: "Earth", Tier: "2"},
+		{Root: "U", Left: "D", Right: "C", Tier: "3"},
+	}, Element.BaseComponents)
+}
+
+// Tree satu base component
+func Leaf(name string) Element.Tree {
+	return Element.Tree{Root: Element.Element{Root: name, Tier: "0"}}
+}
+
+// Tree root = left + right dengan subtree children
+func Node(root, left, right string, children ...Element.Tree) Element.Tree {
+	return Element.Tree{Root: Element.Element{Root: root, Left: left, Right: right}, Children: children}
+}
+
+// Elemen name muncul di mana pun di tree
+func Contains(t Element.Tree, name string) bool {
+	if strings.EqualFold(t.Root.Root, name) {
+		return true
+	}
+	for _, child := range t.Children {
+		if Contains(child, name) {
+			return true
+		}
+	}
+	return false
+}
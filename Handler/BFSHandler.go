@@ -1,34 +1,22 @@
 package handler
 
-import (
-	"encoding/json"
-	"fmt"
-	"net/http"
-	bfs "stima-2-be/BFS"
-	"stima-2-be/Element"
-	"strconv"
-)
+import "net/http"
 
 func BFSHandler(w http.ResponseWriter, r *http.Request) {
-	name := r.URL.Query().Get("element")
-	countStr := r.URL.Query().Get("count")
-	count, err := strconv.Atoi(countStr)
-	if err != nil {
-		fmt.Println("Conversion error:", err)
-	} else {
-		fmt.Println("Converted int:", count)
+	params, ok := parseSearchParams(w, r, "bfs")
+	if !ok {
+		return
 	}
+	ds := params.Dataset
 
-	recipeMap := Element.BuildRecipeMap(Element.GetAllElement())
-	result, info := bfs.MultipleRecipe(name, recipeMap, count)
+	serveCached(w, r, params.Generation, searchCacheKey("/BFS", params), func(w http.ResponseWriter) {
+		result, info, err := runSearch(r.Context(), params.Algorithm, params.Element, ds, params.Count, params.Constraints)
+		if err != nil {
+			writeSearchStopped(w, r)
+			return
+		}
 
-	Element.AreAllTreesUnique(result)
-
-	response := []interface{}{info, result}
-
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Error encoding JSON", http.StatusInternalServerError)
-		fmt.Println("JSON encode error:", err)
-	}
+		w.Header().Set("X-Dataset-Version", ds.Version)
+		writeSearchResult(w, r, params.Output, info, result)
+	})
 }
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"stima-2-be/Element"
+	logging "stima-2-be/Logging"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Batas /batch, diisi dari config di main
+var (
+	BatchMaxQueries = 500
+	// Jumlah query yang dikerjakan barengan dalam satu batch, dibatasi juga jumlah slot Searches
+	BatchConcurrency = runtime.NumCPU()
+	// Total node yang boleh dikunjungi satu batch, 0 = tanpa batas
+	BatchNodeBudget int64 = 5000000
+)
+
+type BatchQuery struct {
+	Element   string `json:"element"`
+	Algorithm string `json:"algorithm"`
+	Count     int    `json:"count"`
+	// Opsional, sama seperti parameter exclude/require/max_depth/max_nodes di /BFS
+	Exclude  []string `json:"exclude,omitempty"`
+	Require  []string `json:"require,omitempty"`
+	MaxDepth int      `json:"max_depth,omitempty"`
+	MaxNodes int      `json:"max_nodes,omitempty"`
+}
+
+// Dataset dan pack berlaku untuk semua query, jadi recipe map cuma dibangun sekali
+type BatchRequest struct {
+	Dataset    string       `json:"dataset"`
+	Packs      []string     `json:"packs"`
+	NodeBudget int64        `json:"node_budget"`
+	Queries    []BatchQuery `json:"queries"`
+}
+
+type BatchResult struct {
+	Query BatchQuery `json:"query"`
+	// ok, error (query tidak valid), partial (berhenti di tengah jalan, trees belum lengkap)
+	// atau skipped (ga sempat dijalankan)
+	Status  string                 `json:"status"`
+	Metrics *Element.MetricsResult `json:"metrics,omitempty"`
+	Trees   []Element.Tree         `json:"trees"`
+	Error   *APIError              `json:"error,omitempty"`
+}
+
+type BatchSummary struct {
+	Queries      int   `json:"queries"`
+	Succeeded    int   `json:"succeeded"`
+	Failed       int   `json:"failed"`
+	Partial      int   `json:"partial"`
+	Skipped      int   `json:"skipped"`
+	NodesVisited int64 `json:"nodes_visited"`
+	NodeBudget   int64 `json:"node_budget"`
+	DurationMs   int64 `json:"duration_ms"`
+}
+
+type BatchResponse struct {
+	Dataset        string        `json:"dataset"`
+	DatasetVersion string        `json:"dataset_version"`
+	Summary        BatchSummary  `json:"summary"`
+	Results        []BatchResult `json:"results"`
+}
+
+// Budget node yang dipakai: permintaan client, dibatasi budget server
+func batchBudget(requested int64) int64 {
+	if requested <= 0 {
+		return BatchNodeBudget
+	}
+	if BatchNodeBudget > 0 && requested > BatchNodeBudget {
+		return BatchNodeBudget
+	}
+	return requested
+}
+
+func BatchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	var req BatchRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid_body", "Body harus berupa objek JSON dengan field queries")
+		return
+	}
+	if len(req.Queries) == 0 {
+		writeError(w, r, http.StatusBadRequest, "invalid_body", "queries tidak boleh kosong")
+		return
+	}
+	if len(req.Queries) > BatchMaxQueries {
+		writeError(w, r, http.StatusBadRequest, "too_many_queries",
+			fmt.Sprintf("Maksimal %d query per batch, diterima %d", BatchMaxQueries, len(req.Queries)))
+		return
+	}
+
+	ds, ok := resolveDataset(w, r, req.Dataset, req.Packs)
+	if !ok {
+		return
+	}
+	budget := batchBudget(req.NodeBudget)
+
+	start := time.Now()
+	results, summary := runBatch(r.Context(), ds, req.Queries, budget)
+	summary.DurationMs = time.Since(start).Milliseconds()
+	response := BatchResponse{
+		Dataset:        ds.Name,
+		DatasetVersion: ds.Version,
+		Summary:        summary,
+		Results:        results,
+	}
+
+	logging.FromContext(r.Context()).Info("batch selesai",
+		"queries", response.Summary.Queries,
+		"failed", response.Summary.Failed,
+		"partial", response.Summary.Partial,
+		"skipped", response.Summary.Skipped,
+		"nodes_visited", response.Summary.NodesVisited)
+	w.Header().Set("X-Dataset-Version", ds.Version)
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// Kerjain semua query pakai worker pool, urutan hasil sama dengan urutan query.
+// Tiap query ngambil slot Searches sendiri, jadi batch tetap kena batas pencarian global
+func runBatch(ctx context.Context, ds *Element.Dataset, queries []BatchQuery, budget int64) ([]BatchResult, BatchSummary) {
+	results := make([]BatchResult, len(queries))
+	var used atomic.Int64
+
+	workers := BatchConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if size := Searches.Size(); size > 0 && workers > size {
+		workers = size
+	}
+	if workers > len(queries) {
+		workers = len(queries)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				results[idx] = runBatchQuery(ctx, ds, queries[idx], budget, &used)
+			}
+		}()
+	}
+	for i := range queries {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	summary := BatchSummary{Queries: len(queries), NodesVisited: used.Load(), NodeBudget: budget}
+	for _, result := range results {
+		switch result.Status {
+		case "ok":
+			summary.Succeeded++
+		case "error":
+			summary.Failed++
+		case "partial":
+			summary.Partial++
+		default:
+			summary.Skipped++
+		}
+	}
+	return results, summary
+}
+
+func runBatchQuery(ctx context.Context, ds *Element.Dataset, q BatchQuery, budget int64, used *atomic.Int64) BatchResult {
+	q.Element = strings.TrimSpace(q.Element)
+	q.Algorithm = strings.ToLower(q.Algorithm)
+	if q.Algorithm == "" {
+		q.Algorithm = "bfs"
+	}
+	if q.Count == 0 {
+		q.Count = 1
+	}
+	result := BatchResult{Query: q, Trees: []Element.Tree{}}
+
+	fail := func(status string, apiErr APIError) BatchResult {
+		result.Status = status
+		result.Error = &apiErr
+		return result
+	}
+	switch {
+	case q.Element == "":
+		return fail("error", APIError{Code: "missing_element", Message: "Field element wajib diisi"})
+	case q.Count < 1 || q.Count > MaxCount:
+		return fail("error", invalidCountError())
+	case !supportedAlgorithms[q.Algorithm]:
+		return fail("error", invalidAlgorithmError(q.Algorithm))
+	case !ds.HasElement(q.Element):
+		return fail("error", elementNotFoundError(ds, q.Element))
+	}
+	c, _, apiErr := buildConstraints(ds, q.Element, q.Exclude, q.Require, q.MaxDepth, q.MaxNodes)
+	if apiErr != nil {
+		return fail("error", *apiErr)
+	}
+
+	if ctx.Err() != nil {
+		return fail("skipped", cancelledError())
+	}
+	if budget > 0 && used.Load() >= budget {
+		return fail("skipped", budgetExhaustedError(budget))
+	}
+
+	release, ok := Searches.Acquire(ctx)
+	if !ok {
+		if ctx.Err() != nil {
+			return fail("skipped", cancelledError())
+		}
+		return fail("skipped", APIError{Code: "server_busy", Message: "Server sedang sibuk memproses pencarian lain"})
+	}
+	// Node dihitung langsung ke used selama pencarian, jadi query yang lagi jalan
+	// ikut berhenti begitu budget habis atau request dibatalkan
+	stats := Element.NewBudgetedSearchStats(ctx, used, budget)
+	trees, info, _ := runSearchWithStats(q.Algorithm, q.Element, ds, q.Count, c, stats)
+	release()
+
+	result.Status = "ok"
+	result.Metrics = &info
+	if trees != nil {
+		result.Trees = trees
+	}
+	if err := stats.Err(); err != nil {
+		apiErr := cancelledError()
+		if errors.Is(err, Element.ErrBudgetExhausted) {
+			apiErr = budgetExhaustedError(budget)
+		}
+		result.Status = "partial"
+		result.Error = &apiErr
+	}
+	return result
+}
+
+func cancelledError() APIError {
+	return APIError{Code: "cancelled", Message: "Request dibatalkan sebelum query ini selesai"}
+}
+
+func budgetExhaustedError(budget int64) APIError {
+	return APIError{
+		Code:    "budget_exhausted",
+		Message: fmt.Sprintf("Budget %d node untuk batch ini sudah habis", budget),
+	}
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	cache "stima-2-be/Cache"
+	"stima-2-be/Element"
+	limit "stima-2-be/Limit"
+	logging "stima-2-be/Logging"
+	metrics "stima-2-be/Metrics"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cache response pencarian, nil berarti cache mati (diisi dari config di main)
+var ResponseCache *cache.Cache
+
+// Slot pencarian, cuma dipakai kalau cache miss biar hit ga ikut antri.
+// nil berarti tanpa batas
+var Searches *limit.Semaphore
+
+// Beda tiap proses, biar ETag lama ga dianggap valid setelah restart
+var etagSeed = strconv.FormatInt(time.Now().UnixNano(), 36)
+
+// Key dari generasi data, versi dataset dan query yang udah dinormalisasi
+func searchCacheKey(route string, params searchParams, extra ...string) string {
+	parts := []string{
+		route,
+		strconv.FormatUint(params.Generation, 10),
+		strings.ToLower(params.Dataset.Name),
+		params.Dataset.Version,
+		params.Algorithm,
+		strings.ToLower(params.Element),
+		strconv.Itoa(params.Count),
+		params.Output.Format,
+		strconv.FormatBool(params.Output.Layout),
+		params.Constraints.Key(),
+	}
+	return strings.Join(append(parts, extra...), "|")
+}
+
+// ETag weak, body ulang bisa beda di bagian metrics tapi isinya setara
+func etagFor(key string) string {
+	sum := sha256.Sum256([]byte(etagSeed + "|" + key))
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// Cocokin If-None-Match (bisa daftar dipisah koma atau "*"), weak comparison
+func etagMatches(header string, etag string) bool {
+	if header == "" {
+		return false
+	}
+	target := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == target {
+			return true
+		}
+	}
+	return false
+}
+
+// ResponseWriter yang nampung response di memory biar bisa disimpan ke cache
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
+
+func setCacheHeaders(w http.ResponseWriter, etag string, status string) {
+	w.Header().Set("ETag", etag)
+	// Browser boleh nyimpen tapi wajib revalidasi, jawabannya 304 kalau data belum berubah
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Cache", status)
+}
+
+// Layani dari If-None-Match atau cache kalau bisa, kalau nggak jalanin render
+// lalu simpan hasilnya. Response selain 200 ga di-cache, begitu juga kalau
+// data berubah waktu render (generation = Generation() saat dataset diambil)
+func serveCached(w http.ResponseWriter, r *http.Request, generation uint64, key string, render func(w http.ResponseWriter)) {
+	etag := etagFor(key)
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		metrics.ObserveCache("not_modified")
+		setCacheHeaders(w, etag, "HIT")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if entry, ok := ResponseCache.Get(key); ok {
+		metrics.ObserveCache("hit")
+		setCacheHeaders(w, etag, "HIT")
+		w.Header().Set("Content-Type", entry.ContentType)
+		w.Header().Set("X-Dataset-Version", entry.DatasetVersion)
+		w.Write(entry.Body)
+		return
+	}
+
+	status := "MISS"
+	if ResponseCache == nil {
+		status = "BYPASS"
+	}
+	metrics.ObserveCache(strings.ToLower(status))
+
+	buf := &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+	if !Searches.Do(w, r, func() { render(buf) }) {
+		return
+	}
+	for k, v := range buf.header {
+		w.Header()[k] = v
+	}
+	if buf.status == http.StatusOK {
+		if Element.Generation() == generation {
+			ResponseCache.Put(key, cache.Entry{
+				Body:           buf.body.Bytes(),
+				ContentType:    buf.header.Get("Content-Type"),
+				DatasetVersion: buf.header.Get("X-Dataset-Version"),
+			})
+		}
+		setCacheHeaders(w, etag, status)
+	}
+	w.WriteHeader(buf.status)
+	w.Write(buf.body.Bytes())
+}
+
+// GET: statistik cache, DELETE: kosongin cache (admin)
+func CacheHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, r, http.StatusOK, map[string]interface{}{
+			"enabled": ResponseCache != nil,
+			"stats":   ResponseCache.Stats(),
+		})
+	case http.MethodDelete:
+		ResponseCache.Purge()
+		logging.FromContext(r.Context()).Info("cache response dikosongkan")
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
@@ -0,0 +1,34 @@
+package handler
+
+import "testing"
+
+func TestEtagMatches(t *testing.T) {
+	etag := `W/"abc"`
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{`W/"abc"`, true},
+		{`"abc"`, true},
+		{`W/"xyz"`, false},
+		{`W/"xyz", W/"abc"`, true},
+		{`"xyz","abc"`, true},
+		{"*", true},
+		{`W/"ab"`, false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, etag); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, mau %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestEtagForStable(t *testing.T) {
+	if etagFor("a|b") != etagFor("a|b") {
+		t.Error("ETag untuk key yang sama harus sama")
+	}
+	if etagFor("a|b") == etagFor("a|c") {
+		t.Error("ETag untuk key berbeda harus beda")
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"stima-2-be/Element"
+	"strconv"
+	"strings"
+)
+
+type CommonResponse struct {
+	A                    string                     `json:"a"`
+	B                    string                     `json:"b"`
+	Dataset              string                     `json:"dataset"`
+	DatasetVersion       string                     `json:"dataset_version"`
+	SharedTotal          int                        `json:"shared_total"`
+	SharedIntermediates  []Element.SharedIngredient `json:"shared_intermediates"`
+	SharedBaseComponents []string                   `json:"shared_base_components"`
+	Plan                 Element.CombinedPlan       `json:"plan"`
+}
+
+// Elemen antara yang dipakai bareng oleh dua target + rencana crafting gabungan.
+// Sama seperti endpoint search lain, hasilnya di-cache dan hitungannya pakai slot pencarian
+func CommonHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	a := strings.TrimSpace(q.Get("a"))
+	b := strings.TrimSpace(q.Get("b"))
+	if a == "" || b == "" {
+		writeError(w, r, http.StatusBadRequest, "missing_element", "Parameter a dan b wajib diisi")
+		return
+	}
+
+	limit := 20
+	if limitStr := q.Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 1 || l > MaxCount {
+			writeError(w, r, http.StatusBadRequest, "invalid_limit",
+				fmt.Sprintf("Parameter limit harus bilangan bulat antara 1 dan %d", MaxCount))
+			return
+		}
+		limit = l
+	}
+
+	// Diambil sebelum lookup dataset biar hasil lama ga kesimpen di generasi baru
+	generation := Element.Generation()
+	ds, ok := datasetFromRequest(w, r)
+	if !ok {
+		return
+	}
+	for _, name := range []string{a, b} {
+		if !ds.HasElement(name) {
+			writeAPIError(w, r, http.StatusNotFound, elementNotFoundError(ds, name))
+			return
+		}
+	}
+	a, b = ds.DisplayName(a), ds.DisplayName(b)
+
+	key := strings.Join([]string{
+		"/Elements/common",
+		strconv.FormatUint(generation, 10),
+		strings.ToLower(ds.Name),
+		ds.Version,
+		strings.ToLower(a),
+		strings.ToLower(b),
+		strconv.Itoa(limit),
+	}, "|")
+	serveCached(w, r, generation, key, func(w http.ResponseWriter) {
+		plan, missing := ds.PlanTwo(a, b)
+		if missing != "" {
+			writeError(w, r, http.StatusUnprocessableEntity, "no_recipe",
+				fmt.Sprintf("%s tidak bisa dibuat dari base component dataset %s", missing, ds.Name))
+			return
+		}
+
+		shared, bases := ds.SharedIngredients(a, b)
+		response := CommonResponse{
+			A:                    a,
+			B:                    b,
+			Dataset:              ds.Name,
+			DatasetVersion:       ds.Version,
+			SharedTotal:          len(shared),
+			SharedIntermediates:  shared,
+			SharedBaseComponents: bases,
+			Plan:                 plan,
+		}
+		if len(response.SharedIntermediates) > limit {
+			response.SharedIntermediates = response.SharedIntermediates[:limit]
+		}
+
+		w.Header().Set("X-Dataset-Version", ds.Version)
+		writeJSON(w, r, http.StatusOK, response)
+	})
+}
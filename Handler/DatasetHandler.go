@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"stima-2-be/Element"
+	"strings"
+)
+
+type datasetInfo struct {
+	Name           string          `json:"name"`
+	Version        string          `json:"version"`
+	Elements       int             `json:"elements"`
+	BaseComponents map[string]bool `json:"base_components"`
+}
+
+// Ambil dataset dari query ?dataset=, default la2, plus pack dari ?packs=a,b
+func datasetFromRequest(w http.ResponseWriter, r *http.Request) (*Element.Dataset, bool) {
+	return resolveDataset(w, r, r.URL.Query().Get("dataset"), splitList(r.URL.Query().Get("packs")))
+}
+
+// Dataset + pack overlay dari nama, kalau gagal langsung nulis error 404.
+// Pack cuma bisa dipakai di atas dataset tempat dia divalidasi waktu upload (400)
+func resolveDataset(w http.ResponseWriter, r *http.Request, name string, packNames []string) (*Element.Dataset, bool) {
+	ds, exists := Element.GetDataset(name)
+	if !exists {
+		writeError(w, r, http.StatusNotFound, "dataset_not_found", "Dataset tidak ditemukan: "+name)
+		return nil, false
+	}
+
+	var overlays []*Element.Pack
+	for _, packName := range packNames {
+		p, exists := Element.GetPack(packName)
+		if !exists {
+			writeError(w, r, http.StatusNotFound, "pack_not_found", "Pack tidak ditemukan: "+packName)
+			return nil, false
+		}
+		if !strings.EqualFold(p.BaseDataset(), ds.Name) {
+			writeError(w, r, http.StatusBadRequest, "pack_dataset_mismatch",
+				fmt.Sprintf("Pack %s dibuat untuk dataset %s, tidak bisa dipakai di dataset %s", p.Name, p.BaseDataset(), ds.Name))
+			return nil, false
+		}
+		overlays = append(overlays, p)
+	}
+
+	return Element.Overlay(ds, overlays...), true
+}
+
+func DatasetListHandler(w http.ResponseWriter, r *http.Request) {
+	var result []datasetInfo
+	for _, ds := range Element.ListDatasets() {
+		result = append(result, datasetInfo{
+			Name:           ds.Name,
+			Version:        ds.Version,
+			Elements:       len(ds.Elements),
+			BaseComponents: ds.BaseComponents,
+		})
+	}
+
+	writeJSON(w, r, http.StatusOK, result)
+}
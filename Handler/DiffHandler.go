@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"stima-2-be/Element"
+	logging "stima-2-be/Logging"
+	"stima-2-be/scrapper"
+)
+
+func DiffHandler(w http.ResponseWriter, r *http.Request) {
+	diff, err := Element.LoadDiffFromFile(scrapper.DiffFile)
+	if err != nil {
+		writeError(w, r, http.StatusNotFound, "diff_not_found", "Belum ada diff, lakukan scrape terlebih dahulu")
+		logging.FromContext(r.Context()).Debug("load diff error", "err", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(diff); err != nil {
+		http.Error(w, "Error encoding JSON", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("json encode error", "err", err)
+	}
+}
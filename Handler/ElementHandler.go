@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Batas jumlah hasil autocomplete
+const MaxSearchLimit = 100
+
+// Autocomplete / fuzzy search nama elemen
+func ElementSearchHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := strings.TrimSpace(q.Get("q"))
+	if query == "" {
+		writeError(w, r, http.StatusBadRequest, "missing_query", "Parameter q wajib diisi")
+		return
+	}
+
+	limit := 10
+	if limitStr := q.Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 1 || l > MaxSearchLimit {
+			writeError(w, r, http.StatusBadRequest, "invalid_limit",
+				fmt.Sprintf("Parameter limit harus bilangan bulat antara 1 dan %d", MaxSearchLimit))
+			return
+		}
+		limit = l
+	}
+
+	ds, ok := datasetFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("X-Dataset-Version", ds.Version)
+	writeJSON(w, r, http.StatusOK, ds.SearchElements(query, limit))
+}
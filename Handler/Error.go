@@ -0,0 +1,27 @@
+package handler
+
+import "net/http"
+
+// Body error JSON: {"error": {"code": ..., "message": ...}}
+type APIError struct {
+	Code        string   `json:"code"`
+	Message     string   `json:"message"`
+	Suggestions []string `json:"suggestions,omitempty"`
+	Details     []string `json:"details,omitempty"`
+}
+
+type errorResponse struct {
+	Error APIError `json:"error"`
+}
+
+func writeAPIError(w http.ResponseWriter, r *http.Request, status int, apiErr APIError) {
+	writeJSON(w, r, status, errorResponse{Error: apiErr})
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, code string, message string) {
+	writeAPIError(w, r, status, APIError{Code: code, Message: message})
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed")
+}
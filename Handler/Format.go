@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"stima-2-be/Element"
+	logging "stima-2-be/Logging"
+	render "stima-2-be/Render"
+	"strconv"
+)
+
+// Format output yang didukung endpoint search
+var supportedFormats = map[string]bool{
+	"json":       true,
+	"dot":        true,
+	"mermaid":    true,
+	"steps":      true,
+	"steps-text": true,
+}
+
+type outputOptions struct {
+	Format string
+	// Tambahin koordinat tidy-tree layout di tiap node (khusus format json)
+	Layout bool
+}
+
+func outputFromRequest(w http.ResponseWriter, r *http.Request) (outputOptions, bool) {
+	var opts outputOptions
+	opts.Format = r.URL.Query().Get("format")
+	if opts.Format == "" {
+		opts.Format = "json"
+	}
+	if !supportedFormats[opts.Format] {
+		writeError(w, r, http.StatusBadRequest, "invalid_format", "Format tidak didukung: "+opts.Format)
+		return opts, false
+	}
+
+	if layoutStr := r.URL.Query().Get("layout"); layoutStr != "" {
+		layout, err := strconv.ParseBool(layoutStr)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid_layout", "Parameter layout harus true/false")
+			return opts, false
+		}
+		opts.Layout = layout
+	}
+	return opts, true
+}
+
+// Tulis hasil search sesuai format yang diminta
+func writeSearchResult(w http.ResponseWriter, r *http.Request, opts outputOptions, info Element.MetricsResult, result []Element.Tree) {
+	switch opts.Format {
+	case "dot":
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		fmt.Fprint(w, render.DOT(result))
+	case "mermaid":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, render.Mermaid(result))
+	case "steps-text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, render.AllStepsText(result))
+	case "steps":
+		writeJSONResponse(w, r, []interface{}{info, render.AllSteps(result)})
+	default:
+		if opts.Layout {
+			writeJSONResponse(w, r, []interface{}{info, render.LayoutAll(result)})
+			return
+		}
+		writeJSONResponse(w, r, []interface{}{info, result})
+	}
+}
+
+func writeJSONResponse(w http.ResponseWriter, r *http.Request, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, "Error encoding JSON", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("json encode error", "err", err)
+	}
+}
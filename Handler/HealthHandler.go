@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"stima-2-be/Element"
+	snapshot "stima-2-be/Snapshot"
+	"time"
+)
+
+// Minimal elemen dataset default supaya instance dianggap siap, diisi dari config
+var ReadyMinElements = 1
+
+var startedAt = time.Now()
+
+type readinessCheck struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+}
+
+type readinessResponse struct {
+	Status         string           `json:"status"`
+	DatasetVersion string           `json:"dataset_version,omitempty"`
+	Elements       int              `json:"elements"`
+	MinElements    int              `json:"min_elements"`
+	Reloading      bool             `json:"reloading"`
+	LastLoadedAt   *time.Time       `json:"last_loaded_at,omitempty"`
+	LastLoadError  string           `json:"last_load_error,omitempty"`
+	LastErrorAt    *time.Time       `json:"last_load_error_at,omitempty"`
+	Checks         []readinessCheck `json:"checks"`
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// Proses hidup, ga ngecek dataset
+func HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"status":         "ok",
+		"uptime_seconds": int64(time.Since(startedAt).Seconds()),
+	})
+}
+
+// Siap nerima traffic kalau dataset default sudah ke-load dan cukup isinya
+func ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	ds := Element.Default()
+	st := snapshot.CurrentStatus()
+	elements := len(ds.Elements)
+
+	resp := readinessResponse{
+		DatasetVersion: ds.Version,
+		Elements:       elements,
+		MinElements:    ReadyMinElements,
+		Reloading:      st.Reloading,
+		LastLoadedAt:   timePtr(st.LastLoadedAt),
+		LastLoadError:  st.LastError,
+		LastErrorAt:    timePtr(st.LastErrorAt),
+	}
+
+	loaded := readinessCheck{Name: "dataset_loaded", OK: elements > 0}
+	if !loaded.OK {
+		loaded.Message = "dataset default masih kosong, lakukan scrape atau aktifkan snapshot"
+		if st.LastError != "" {
+			loaded.Message += " (load terakhir gagal: " + st.LastError + ")"
+		}
+	}
+
+	count := readinessCheck{Name: "element_count", OK: elements >= ReadyMinElements}
+	if !count.OK {
+		count.Message = fmt.Sprintf("%d elemen, minimal %d", elements, ReadyMinElements)
+	}
+
+	// Reload tanpa snapshot sebelumnya berarti belum ada data yang bisa dipakai
+	reload := readinessCheck{Name: "reload", OK: !st.Reloading || st.Active.Version != ""}
+	if !reload.OK {
+		reload.Message = "reload pertama sedang berjalan, belum ada snapshot sebelumnya"
+	}
+
+	resp.Checks = []readinessCheck{loaded, count, reload}
+	status := http.StatusOK
+	resp.Status = "ok"
+	for _, c := range resp.Checks {
+		if !c.OK {
+			status = http.StatusServiceUnavailable
+			resp.Status = "unavailable"
+		}
+	}
+	writeJSON(w, r, status, resp)
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"stima-2-be/Element"
+	logging "stima-2-be/Logging"
+	"time"
+)
+
+var packNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+type packInfo struct {
+	Name       string    `json:"name"`
+	Dataset    string    `json:"dataset"`
+	UploadedAt time.Time `json:"uploaded_at"`
+	Elements   int       `json:"elements"`
+	// Resep dataset dasar yang tier-nya diganti pack, cuma ada di response upload
+	Overrides []string `json:"overrides,omitempty"`
+}
+
+// GET daftar pack, POST upload pack, DELETE hapus pack
+func PackHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		listPacks(w, r)
+	case http.MethodPost:
+		uploadPack(w, r)
+	case http.MethodDelete:
+		deletePack(w, r)
+	default:
+		methodNotAllowed(w, r)
+	}
+}
+
+func listPacks(w http.ResponseWriter, r *http.Request) {
+	result := []packInfo{}
+	for _, p := range Element.ListPacks() {
+		result = append(result, packInfo{
+			Name:       p.Name,
+			Dataset:    p.BaseDataset(),
+			UploadedAt: p.UploadedAt,
+			Elements:   len(p.Elements),
+		})
+	}
+	writeJSON(w, r, http.StatusOK, result)
+}
+
+func uploadPack(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if !packNameRegex.MatchString(name) {
+		writeError(w, r, http.StatusBadRequest, "invalid_pack_name", "Nama pack hanya boleh huruf, angka, '-' dan '_'")
+		return
+	}
+
+	// Pack divalidasi terhadap dataset yang dipilih (default la2) dan nantinya
+	// cuma bisa dipakai di atas dataset itu
+	base, exists := Element.GetDataset(r.URL.Query().Get("dataset"))
+	if !exists {
+		writeError(w, r, http.StatusNotFound, "dataset_not_found", "Dataset tidak ditemukan")
+		return
+	}
+
+	var elements []Element.Element
+	r.Body = http.MaxBytesReader(w, r.Body, 10<<20)
+	if err := json.NewDecoder(r.Body).Decode(&elements); err != nil {
+		writeError(w, r, http.StatusBadRequest, "invalid_body", "Body harus berupa array JSON elemen")
+		return
+	}
+	if len(elements) == 0 {
+		writeError(w, r, http.StatusBadRequest, "invalid_body", "Pack tidak boleh kosong")
+		return
+	}
+
+	problems, overrides := Element.ValidatePack(base, elements)
+	if len(problems) > 0 {
+		writeAPIError(w, r, http.StatusUnprocessableEntity, APIError{
+			Code:    "invalid_pack",
+			Message: "Pack tidak valid",
+			Details: problems,
+		})
+		return
+	}
+
+	p := &Element.Pack{
+		Name:       name,
+		Dataset:    base.Name,
+		UploadedAt: time.Now().UTC(),
+		Elements:   elements,
+	}
+	if err := Element.SavePackToFile(p, Element.PackDir); err != nil {
+		writeError(w, r, http.StatusInternalServerError, "pack_save_failed", "Gagal menyimpan pack")
+		logging.FromContext(r.Context()).Error("save pack error", "pack", name, "err", err)
+		return
+	}
+	Element.SavePack(p)
+	if len(overrides) > 0 {
+		logging.FromContext(r.Context()).Info("pack menimpa resep dataset", "pack", name, "overrides", len(overrides))
+	}
+
+	writeJSON(w, r, http.StatusCreated, packInfo{
+		Name:       p.Name,
+		Dataset:    p.BaseDataset(),
+		UploadedAt: p.UploadedAt,
+		Elements:   len(p.Elements),
+		Overrides:  overrides,
+	})
+}
+
+func deletePack(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if !Element.DeletePack(name) {
+		writeError(w, r, http.StatusNotFound, "pack_not_found", "Pack tidak ditemukan")
+		return
+	}
+	if err := Element.RemovePackFile(name, Element.PackDir); err != nil {
+		logging.FromContext(r.Context()).Error("remove pack error", "pack", name, "err", err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
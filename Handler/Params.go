@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"stima-2-be/Element"
+	"strconv"
+	"strings"
+)
+
+// Batas jumlah resep per request
+const MaxCount = 1000
+
+var supportedAlgorithms = map[string]bool{
+	"bfs": true,
+	"dfs": true,
+}
+
+// Parameter search yang udah divalidasi
+type searchParams struct {
+	Element   string
+	Count     int
+	Algorithm string
+	Dataset   *Element.Dataset
+	Packs     []string
+	Output    outputOptions
+	// nil kalau ga ada exclude/require/max_depth/max_nodes
+	Constraints *Element.Constraints
+	// Generasi data waktu dataset diambil, bagian dari key cache
+	Generation uint64
+}
+
+func splitList(s string) []string {
+	result := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Validasi semua parameter search, kalau gagal langsung nulis error ke response
+func parseSearchParams(w http.ResponseWriter, r *http.Request, algorithm string) (searchParams, bool) {
+	params, ok := parseSearchTarget(w, r, algorithm)
+	if !ok {
+		return params, false
+	}
+
+	if countStr := r.URL.Query().Get("count"); countStr != "" {
+		count, err := strconv.Atoi(countStr)
+		if err != nil || count < 1 || count > MaxCount {
+			writeAPIError(w, r, http.StatusBadRequest, invalidCountError())
+			return params, false
+		}
+		params.Count = count
+	}
+
+	output, ok := outputFromRequest(w, r)
+	if !ok {
+		return params, false
+	}
+	params.Output = output
+	return params, true
+}
+
+// Tolak parameter yang ga dipakai endpoint ini, biar ga diam-diam diabaikan
+func rejectParams(w http.ResponseWriter, r *http.Request, names ...string) bool {
+	for _, name := range names {
+		if r.URL.Query().Has(name) {
+			writeError(w, r, http.StatusBadRequest, "unsupported_parameter",
+				fmt.Sprintf("Parameter %s tidak didukung di %s", name, r.URL.Path))
+			return false
+		}
+	}
+	return true
+}
+
+// Parameter yang nentuin apa yang dicari: elemen, algoritma, dataset + pack dan constraints.
+// Count default 1, output belum diisi
+func parseSearchTarget(w http.ResponseWriter, r *http.Request, algorithm string) (searchParams, bool) {
+	q := r.URL.Query()
+	params := searchParams{
+		Element:   strings.TrimSpace(q.Get("element")),
+		Count:     1,
+		Algorithm: algorithm,
+		Packs:     splitList(q.Get("packs")),
+		// Diambil sebelum lookup dataset biar hasil lama ga kesimpen di generasi baru
+		Generation: Element.Generation(),
+	}
+
+	if params.Element == "" {
+		writeError(w, r, http.StatusBadRequest, "missing_element", "Parameter element wajib diisi")
+		return params, false
+	}
+
+	if params.Algorithm == "" {
+		params.Algorithm = strings.ToLower(q.Get("algorithm"))
+		if params.Algorithm == "" {
+			params.Algorithm = "bfs"
+		}
+	}
+	if !supportedAlgorithms[params.Algorithm] {
+		writeAPIError(w, r, http.StatusBadRequest, invalidAlgorithmError(params.Algorithm))
+		return params, false
+	}
+
+	ds, ok := datasetFromRequest(w, r)
+	if !ok {
+		return params, false
+	}
+	params.Dataset = ds
+
+	if !ds.HasElement(params.Element) {
+		writeAPIError(w, r, http.StatusNotFound, elementNotFoundError(ds, params.Element))
+		return params, false
+	}
+
+	var limits [2]int
+	for i, name := range []string{"max_depth", "max_nodes"} {
+		value := q.Get(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			writeError(w, r, http.StatusBadRequest, "invalid_constraint",
+				fmt.Sprintf("Parameter %s harus bilangan bulat positif", name))
+			return params, false
+		}
+		limits[i] = n
+	}
+	c, status, apiErr := buildConstraints(ds, params.Element,
+		splitList(q.Get("exclude")), splitList(q.Get("require")), limits[0], limits[1])
+	if apiErr != nil {
+		writeAPIError(w, r, status, *apiErr)
+		return params, false
+	}
+	params.Constraints = c
+
+	return params, true
+}
+
+// Validasi constraints pencarian, dipakai bareng endpoint search dan /batch.
+// Kalau gagal balikin status HTTP dan error-nya
+func buildConstraints(ds *Element.Dataset, root string, exclude, require []string, maxDepth, maxNodes int) (*Element.Constraints, int, *APIError) {
+	if maxDepth < 0 || maxNodes < 0 {
+		return nil, http.StatusBadRequest, &APIError{
+			Code:    "invalid_constraint",
+			Message: "max_depth dan max_nodes harus bilangan bulat positif",
+		}
+	}
+
+	for _, name := range append(append([]string{}, exclude...), require...) {
+		if !ds.HasElement(name) {
+			apiErr := elementNotFoundError(ds, name)
+			return nil, http.StatusNotFound, &apiErr
+		}
+	}
+	excluded := make(map[string]bool)
+	for _, name := range exclude {
+		if strings.EqualFold(name, root) {
+			return nil, http.StatusBadRequest, &APIError{
+				Code:    "invalid_constraint",
+				Message: fmt.Sprintf("Elemen yang dicari (%s) tidak boleh ada di exclude", root),
+			}
+		}
+		excluded[strings.ToLower(name)] = true
+	}
+	for _, name := range require {
+		if excluded[strings.ToLower(name)] {
+			return nil, http.StatusBadRequest, &APIError{
+				Code:    "invalid_constraint",
+				Message: fmt.Sprintf("Elemen %s tidak bisa di exclude dan require sekaligus", name),
+			}
+		}
+	}
+
+	return Element.NewConstraints(ds, exclude, require, maxDepth, maxNodes), 0, nil
+}
+
+// Error validasi yang dipakai bareng endpoint search biasa dan /batch
+func invalidCountError() APIError {
+	return APIError{
+		Code:    "invalid_count",
+		Message: fmt.Sprintf("Parameter count harus bilangan bulat antara 1 dan %d", MaxCount),
+	}
+}
+
+func invalidAlgorithmError(algorithm string) APIError {
+	return APIError{Code: "invalid_algorithm", Message: "Algoritma tidak didukung: " + algorithm + " (bfs, dfs)"}
+}
+
+func elementNotFoundError(ds *Element.Dataset, name string) APIError {
+	return APIError{
+		Code:        "element_not_found",
+		Message:     fmt.Sprintf("Elemen %q tidak ada di dataset %s", name, ds.Name),
+		Suggestions: ds.Suggest(name, 5),
+	}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	render "stima-2-be/Render"
+	"strconv"
+)
+
+// Gambar SVG resep ke-index untuk satu elemen. Jumlah resep ditentukan index,
+// jadi count, format dan layout ditolak
+func SVGHandler(w http.ResponseWriter, r *http.Request) {
+	if !rejectParams(w, r, "count", "format", "layout") {
+		return
+	}
+
+	index := 0
+	if indexStr := r.URL.Query().Get("index"); indexStr != "" {
+		i, err := strconv.Atoi(indexStr)
+		if err != nil || i < 0 || i >= MaxCount {
+			writeError(w, r, http.StatusBadRequest, "invalid_index",
+				fmt.Sprintf("Parameter index harus bilangan bulat antara 0 dan %d", MaxCount-1))
+			return
+		}
+		index = i
+	}
+
+	params, ok := parseSearchTarget(w, r, "")
+	if !ok {
+		return
+	}
+	// Resep ke-index baru ketemu setelah index+1 resep dicari
+	params.Count = index + 1
+	ds := params.Dataset
+
+	serveCached(w, r, params.Generation, searchCacheKey("/SVG", params), func(w http.ResponseWriter) {
+		trees, _, err := runSearch(r.Context(), params.Algorithm, params.Element, ds, params.Count, params.Constraints)
+		if err != nil {
+			writeSearchStopped(w, r)
+			return
+		}
+		if index >= len(trees) {
+			writeError(w, r, http.StatusNotFound, "recipe_not_found",
+				fmt.Sprintf("Resep ke-%d untuk %s tidak ditemukan", index, params.Element))
+			return
+		}
+
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("X-Dataset-Version", ds.Version)
+		fmt.Fprint(w, render.SVG(trees[index]))
+	})
+}
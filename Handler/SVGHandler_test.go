@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSVGRejectsUnsupportedParams(t *testing.T) {
+	for _, query := range []string{"count=3", "format=dot", "layout=true"} {
+		t.Run(query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			SVGHandler(rec, httptest.NewRequest(http.MethodGet, "/SVG?element=Steam&"+query, nil))
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, mau 400", rec.Code)
+			}
+			var body errorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error.Code != "unsupported_parameter" {
+				t.Errorf("body = %s, mau kode unsupported_parameter", rec.Body.String())
+			}
+		})
+	}
+}
@@ -1,15 +1,46 @@
 package handler
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
-	"stima-2-be/Element"
+	logging "stima-2-be/Logging"
+	metrics "stima-2-be/Metrics"
+	snapshot "stima-2-be/Snapshot"
 	"stima-2-be/scrapper"
 )
 
+// Scrape ulang wiki lalu aktifkan hasilnya, cuma POST karena nimpa data
 func ScrapHandler(w http.ResponseWriter, r *http.Request) {
-	scrapper.Scrapper()
-	fmt.Fprintln(w, "Scraping selesai.")
-	Element.LoadElementsFromFile("output.json")
-	fmt.Fprintln(w, "Load selesai")
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	done := snapshot.BeginReload()
+	defer done()
+
+	meta, err := scrapper.Scrapper(r.Context())
+	if err != nil {
+		snapshot.RecordError(err)
+	}
+	if errors.Is(err, scrapper.ErrFetch) {
+		writeError(w, r, http.StatusBadGateway, "scrape_failed", err.Error())
+		logging.FromContext(r.Context()).Error("scrape error", "err", err)
+		return
+	}
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, "scrape_failed", "Gagal menyimpan hasil scrape")
+		logging.FromContext(r.Context()).Error("scrape error", "err", err)
+		return
+	}
+	active, err := snapshot.Activate(meta.Version)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, "snapshot_error", "Gagal load snapshot")
+		logging.FromContext(r.Context()).Error("activate snapshot error", "version", meta.Version, "err", err)
+		return
+	}
+	metrics.SetLastScrape(active.CreatedAt)
+
+	// Sama seperti endpoint snapshot, balikin metadata snapshot yang baru aktif
+	writeJSON(w, r, http.StatusOK, active)
 }
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	bfs "stima-2-be/BFS"
+	dfs "stima-2-be/DFS"
+	"stima-2-be/Element"
+	metrics "stima-2-be/Metrics"
+	"time"
+)
+
+// Jalankan search sesuai nama algoritma (bfs/dfs), c boleh nil. Pencarian berhenti
+// kalau ctx dibatalkan (client putus atau server shutdown), errornya dibalikin
+// biar hasil yang belum lengkap ga ikut di-cache
+func runSearch(ctx context.Context, algorithm string, name string, ds *Element.Dataset, count int, c *Element.Constraints) ([]Element.Tree, Element.MetricsResult, error) {
+	stats := Element.NewBudgetedSearchStats(ctx, nil, 0)
+	trees, info, _ := runSearchWithStats(algorithm, name, ds, count, c, stats)
+	return trees, info, stats.Err()
+}
+
+// Response kalau pencarian dihentikan di tengah jalan, status bukan 200 jadi ga di-cache
+func writeSearchStopped(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusServiceUnavailable, "cancelled", "Pencarian dihentikan karena request dibatalkan atau server sedang shutdown")
+}
+
+// Sama seperti runSearch dengan stats dari pemanggil (budget node /batch)
+func runSearchWithStats(algorithm string, name string, ds *Element.Dataset, count int, c *Element.Constraints, stats *Element.SearchStats) ([]Element.Tree, Element.MetricsResult, bool) {
+	start := time.Now()
+	var trees []Element.Tree
+	var info Element.MetricsResult
+	switch algorithm {
+	case "", "bfs":
+		algorithm = "bfs"
+		trees, info = bfs.MultipleRecipeWithStats(name, ds, count, c, stats)
+	case "dfs":
+		trees, info = dfs.MultipleRecipeWithStats(name, ds, count, c, stats)
+	default:
+		return nil, Element.MetricsResult{}, false
+	}
+	info.DatasetVersion = ds.Version
+	metrics.ObserveSearch(algorithm, info, time.Since(start))
+	return trees, info, true
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"stima-2-be/Element"
+	"stima-2-be/Element/elementtest"
+	"sync/atomic"
+	"testing"
+)
+
+var searchAlgorithms = []string{"bfs", "dfs"}
+
+func TestConstraintsNotLostToLimit(t *testing.T) {
+	ds := elementtest.ConstraintsDataset()
+	tests := []struct {
+		name     string
+		element  string
+		require  []string
+		maxNodes int
+	}{
+		// T(A(Air,Earth),B(Fire,Air)) 7 node, subtree A pertama 5 node
+		{"max_nodes", "T", nil, 7},
+		// Steam bisa lewat D atau C, tapi resep pertama keduanya ga pakai Steam
+		{"require lewat dua bahan", "U", []string{"steam"}, 0},
+	}
+	for _, algorithm := range searchAlgorithms {
+		for _, tt := range tests {
+			t.Run(algorithm+"/"+tt.name, func(t *testing.T) {
+				c := Element.NewConstraints(ds, nil, tt.require, 0, tt.maxNodes)
+				trees, info, err := runSearch(context.Background(), algorithm, tt.element, ds, 1, c)
+				if err != nil {
+					t.Fatalf("runSearch: %v", err)
+				}
+				if len(trees) != 1 {
+					t.Fatalf("dapet %d tree, mau 1 (metrics %+v)", len(trees), info)
+				}
+				if tt.maxNodes > 0 && trees[0].Size() > tt.maxNodes {
+					t.Errorf("tree %d node, batas %d", trees[0].Size(), tt.maxNodes)
+				}
+				for _, name := range tt.require {
+					if !elementtest.Contains(trees[0], name) {
+						t.Errorf("tree ga memuat %s", name)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestNoConstraintsUnchanged(t *testing.T) {
+	for _, algorithm := range searchAlgorithms {
+		t.Run(algorithm, func(t *testing.T) {
+			trees, _, err := runSearch(context.Background(), algorithm, "T", elementtest.ConstraintsDataset(), 5, nil)
+			if err != nil {
+				t.Fatalf("runSearch: %v", err)
+			}
+			if len(trees) != 2 {
+				t.Fatalf("dapet %d tree, mau 2", len(trees))
+			}
+		})
+	}
+}
+
+func TestBudgetStopsSearch(t *testing.T) {
+	for _, algorithm := range searchAlgorithms {
+		t.Run(algorithm, func(t *testing.T) {
+			var used atomic.Int64
+			stats := Element.NewBudgetedSearchStats(context.Background(), &used, 3)
+			trees, info, _ := runSearchWithStats(algorithm, "T", elementtest.ConstraintsDataset(), 5, nil, stats)
+			if !errors.Is(stats.Err(), Element.ErrBudgetExhausted) {
+				t.Fatalf("Err = %v, mau ErrBudgetExhausted", stats.Err())
+			}
+			if len(trees) != 0 {
+				t.Errorf("dapet %d tree padahal budget cuma 3 node", len(trees))
+			}
+			if info.NodesVisited > 5 {
+				t.Errorf("nodes_visited = %d, harusnya berhenti dekat budget", info.NodesVisited)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	logging "stima-2-be/Logging"
+	snapshot "stima-2-be/Snapshot"
+)
+
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logging.FromContext(r.Context()).Error("json encode error", "err", err)
+	}
+}
+
+func SnapshotListHandler(w http.ResponseWriter, r *http.Request) {
+	snapshots, active, err := snapshot.List()
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, "snapshot_error", "Gagal membaca daftar snapshot")
+		logging.FromContext(r.Context()).Error("list snapshot error", "err", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, map[string]interface{}{
+		"active":    active,
+		"snapshots": snapshots,
+	})
+}
+
+func SnapshotActivateHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	version := r.URL.Query().Get("version")
+	if version == "" {
+		writeError(w, r, http.StatusBadRequest, "missing_version", "Parameter version wajib diisi")
+		return
+	}
+
+	meta, err := snapshot.Activate(version)
+	if errors.Is(err, snapshot.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, "snapshot_not_found", "Snapshot tidak ditemukan")
+		return
+	}
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, "snapshot_error", "Gagal mengaktifkan snapshot")
+		logging.FromContext(r.Context()).Error("activate snapshot error", "version", version, "err", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, meta)
+}
+
+func SnapshotRollbackHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, r)
+		return
+	}
+
+	meta, err := snapshot.Rollback()
+	if err != nil {
+		writeError(w, r, http.StatusConflict, "rollback_failed", "Gagal rollback: "+err.Error())
+		logging.FromContext(r.Context()).Warn("rollback snapshot error", "err", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, meta)
+}
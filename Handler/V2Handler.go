@@ -0,0 +1,100 @@
+package handler
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+	"stima-2-be/Element"
+	render "stima-2-be/Render"
+	"strings"
+)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// Query yang dipake, dikirim balik di response
+type SearchQuery struct {
+	Element  string   `json:"element"`
+	Count    int      `json:"count"`
+	Dataset  string   `json:"dataset"`
+	Packs    []string `json:"packs"`
+	Layout   bool     `json:"layout"`
+	Exclude  []string `json:"exclude,omitempty"`
+	Require  []string `json:"require,omitempty"`
+	MaxDepth int      `json:"max_depth,omitempty"`
+	MaxNodes int      `json:"max_nodes,omitempty"`
+}
+
+// Response /v2, objek bertipe (bukan array posisional seperti v1)
+type SearchResponse struct {
+	APIVersion     string                `json:"api_version"`
+	Query          SearchQuery           `json:"query"`
+	Algorithm      string                `json:"algorithm"`
+	Dataset        string                `json:"dataset"`
+	DatasetVersion string                `json:"dataset_version"`
+	Metrics        Element.MetricsResult `json:"metrics"`
+	// Selalu lengkap dengan koordinat layout biar skemanya cuma satu
+	Trees    []render.LayoutNode `json:"trees"`
+	Warnings []string            `json:"warnings"`
+}
+
+func V2SearchHandler(w http.ResponseWriter, r *http.Request) {
+	params, ok := parseSearchParams(w, r, "")
+	if !ok {
+		return
+	}
+	if params.Output.Format != "json" {
+		writeError(w, r, http.StatusBadRequest, "invalid_format", "API v2 hanya mendukung format json")
+		return
+	}
+	ds := params.Dataset
+	// v2 selalu nyertain layout, parameter layout cuma diterima biar client lama ga error
+	params.Output.Layout = true
+
+	// Query di-echo apa adanya di body, jadi ejaan aslinya ikut jadi key
+	key := searchCacheKey("/v2/search", params, params.Element, strings.Join(params.Packs, ","),
+		r.URL.Query().Get("exclude"), r.URL.Query().Get("require"))
+	serveCached(w, r, params.Generation, key, func(w http.ResponseWriter) {
+		trees, info, err := runSearch(r.Context(), params.Algorithm, params.Element, ds, params.Count, params.Constraints)
+		if err != nil {
+			writeSearchStopped(w, r)
+			return
+		}
+
+		warnings := []string{}
+		if len(trees) < params.Count {
+			warnings = append(warnings, fmt.Sprintf("hanya ditemukan %d dari %d resep", len(trees), params.Count))
+		}
+
+		response := SearchResponse{
+			APIVersion: "2",
+			Query: SearchQuery{
+				Element: params.Element,
+				Count:   params.Count,
+				Dataset: ds.Name,
+				Packs:   params.Packs,
+				Layout:  params.Output.Layout,
+			},
+			Algorithm:      params.Algorithm,
+			Dataset:        ds.Name,
+			DatasetVersion: ds.Version,
+			Metrics:        info,
+			Trees:          render.LayoutAll(trees),
+			Warnings:       warnings,
+		}
+		if c := params.Constraints; c != nil {
+			response.Query.Exclude = splitList(r.URL.Query().Get("exclude"))
+			response.Query.Require = splitList(r.URL.Query().Get("require"))
+			response.Query.MaxDepth = c.MaxDepth
+			response.Query.MaxNodes = c.MaxNodes
+		}
+
+		w.Header().Set("X-Dataset-Version", ds.Version)
+		writeJSON(w, r, http.StatusOK, response)
+	})
+}
+
+func OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
@@ -0,0 +1,250 @@
+package limit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Config struct {
+	// Token bucket per IP, rate dalam request/detik. Rate 0 = tanpa limit
+	Rate  float64
+	Burst int
+	// Limit khusus /Scrap
+	ScrapRate  float64
+	ScrapBurst int
+	// Maksimum pencarian yang jalan barengan, sisanya antri
+	MaxConcurrent int
+	QueueSize     int
+	QueueTimeout  time.Duration
+	// Pakai X-Forwarded-For buat nentuin IP (kalau di belakang reverse proxy)
+	TrustProxy bool
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Rate:          5,
+		Burst:         20,
+		ScrapRate:     1.0 / 300,
+		ScrapBurst:    1,
+		MaxConcurrent: runtime.NumCPU(),
+		QueueSize:     32,
+		QueueTimeout:  10 * time.Second,
+	}
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Token bucket per client
+type Limiter struct {
+	rate  float64
+	burst float64
+
+	mu        sync.Mutex
+	clients   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewLimiter(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		rate:      rate,
+		burst:     float64(burst),
+		clients:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Ambil satu token, kalau habis balikin berapa lama harus nunggu
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if l.rate <= 0 {
+		return true, 0
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, exists := l.clients[key]
+	if !exists {
+		b = &bucket{tokens: l.burst, last: now}
+		l.clients[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// Buang bucket yang udah penuh lagi biar map ga numpuk
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	full := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, b := range l.clients {
+		if now.Sub(b.last) >= full {
+			delete(l.clients, key)
+		}
+	}
+}
+
+// Batas pencarian yang jalan barengan, kelebihannya antri sampai timeout
+type Semaphore struct {
+	slots    chan struct{}
+	waiting  atomic.Int64
+	maxQueue int64
+	timeout  time.Duration
+}
+
+func NewSemaphore(size int, queue int, timeout time.Duration) *Semaphore {
+	if size < 1 {
+		size = 1
+	}
+	return &Semaphore{
+		slots:    make(chan struct{}, size),
+		maxQueue: int64(queue),
+		timeout:  timeout,
+	}
+}
+
+func (s *Semaphore) release() {
+	<-s.slots
+}
+
+// Ambil slot, false kalau antrian penuh, timeout atau request dibatalkan.
+// Semaphore nil selalu dapet slot
+func (s *Semaphore) Acquire(ctx context.Context) (func(), bool) {
+	if s == nil {
+		return func() {}, true
+	}
+	select {
+	case s.slots <- struct{}{}:
+		return s.release, true
+	default:
+	}
+
+	if s.waiting.Add(1) > s.maxQueue {
+		s.waiting.Add(-1)
+		return nil, false
+	}
+	defer s.waiting.Add(-1)
+
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+	select {
+	case s.slots <- struct{}{}:
+		return s.release, true
+	case <-timer.C:
+		return nil, false
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// Jumlah slot, 0 berarti tanpa batas (semaphore nil)
+func (s *Semaphore) Size() int {
+	if s == nil {
+		return 0
+	}
+	return cap(s.slots)
+}
+
+// Slot yang lagi kepake, buat gauge /metrics
+func (s *Semaphore) InFlight() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.slots)
+}
+
+// Jumlah yang lagi antri nunggu slot, buat gauge /metrics
+func (s *Semaphore) Waiting() int64 {
+	if s == nil {
+		return 0
+	}
+	return s.waiting.Load()
+}
+
+// IP client, X-Forwarded-For cuma dipercaya kalau trustProxy
+func ClientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Format error sama kayak handler: {"error": {"code": ..., "message": ...}}
+func tooManyRequests(w http.ResponseWriter, code string, message string, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+			"details": []string{fmt.Sprintf("Coba lagi dalam %d detik", seconds)},
+		},
+	})
+}
+
+// Middleware limit request per IP
+func PerClient(l *Limiter, trustProxy bool, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ok, wait := l.Allow(ClientIP(r, trustProxy))
+		if !ok {
+			tooManyRequests(w, "rate_limited", "Terlalu banyak request, coba lagi nanti", wait)
+			return
+		}
+		next.ServeHTTP(w, r)
+	}
+}
+
+// Jalanin fn dalam satu slot, kalau ga kebagian langsung nulis 429 dan balikin false.
+// Semaphore nil berarti tanpa batas
+func (s *Semaphore) Do(w http.ResponseWriter, r *http.Request, fn func()) bool {
+	if s == nil {
+		fn()
+		return true
+	}
+	release, ok := s.Acquire(r.Context())
+	if !ok {
+		tooManyRequests(w, "server_busy", "Server sedang sibuk memproses pencarian lain, coba lagi nanti", s.timeout)
+		return false
+	}
+	defer release()
+	fn()
+	return true
+}
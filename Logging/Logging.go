@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+type contextKey struct{}
+
+// Parse level dari string (debug, info, warn, error)
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(strings.ToLower(s))); err != nil {
+		return slog.LevelInfo, fmt.Errorf("level log tidak valid: %q (debug, info, warn, error)", s)
+	}
+	return level, nil
+}
+
+// Pasang logger default, format "json" atau "text"
+func Setup(out io.Writer, level string, format string) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "", "json":
+		h = slog.NewJSONHandler(out, opts)
+	case "text":
+		h = slog.NewTextHandler(out, opts)
+	default:
+		return fmt.Errorf("format log tidak valid: %q (json, text)", format)
+	}
+
+	slog.SetDefault(slog.New(h))
+	return nil
+}
+
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// Logger milik request (sudah ada request_id), fallback ke logger default
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// Nyimpen status code yang ditulis handler, dipakai juga middleware metrics
+type StatusRecorder struct {
+	http.ResponseWriter
+	Status int
+}
+
+// Bungkus w, kalau w udah StatusRecorder (middleware sebelumnya) langsung dipakai ulang
+func NewStatusRecorder(w http.ResponseWriter) *StatusRecorder {
+	if recorder, ok := w.(*StatusRecorder); ok {
+		return recorder
+	}
+	return &StatusRecorder{ResponseWriter: w, Status: http.StatusOK}
+}
+
+func (s *StatusRecorder) WriteHeader(status int) {
+	s.Status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Middleware: kasih request ID (pakai header X-Request-ID kalau dikirim client),
+// taruh logger di context, dan log tiap request yang selesai
+func RequestID(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > 64 {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		logger := slog.Default().With("request_id", id)
+		r = r.WithContext(WithLogger(r.Context(), logger))
+
+		start := time.Now()
+		recorder := NewStatusRecorder(w)
+		next.ServeHTTP(recorder, r)
+
+		logger.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"query", r.URL.RawQuery,
+			"status", recorder.Status,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+	}
+}
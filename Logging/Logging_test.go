@@ -0,0 +1,23 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusRecorderReused(t *testing.T) {
+	rec := httptest.NewRecorder()
+	outer := NewStatusRecorder(rec)
+	if outer.Status != http.StatusOK {
+		t.Errorf("status awal = %d, mau 200", outer.Status)
+	}
+	inner := NewStatusRecorder(outer)
+	if inner != outer {
+		t.Fatal("writer yang udah StatusRecorder harus dipakai ulang, bukan dibungkus lagi")
+	}
+	inner.WriteHeader(http.StatusTeapot)
+	if outer.Status != http.StatusTeapot || rec.Code != http.StatusTeapot {
+		t.Errorf("status = %d / %d, mau 418", outer.Status, rec.Code)
+	}
+}
@@ -0,0 +1,304 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"runtime"
+	"sort"
+	cache "stima-2-be/Cache"
+	"stima-2-be/Element"
+	limit "stima-2-be/Limit"
+	logging "stima-2-be/Logging"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Exposition format Prometheus ditulis manual, ga perlu client library
+
+var (
+	durationBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+	depthBuckets    = []float64{1, 2, 4, 8, 16, 32, 64, 128}
+	nodesBuckets    = []float64{10, 50, 100, 500, 1000, 5000, 10000, 50000, 100000, 500000, 1000000}
+)
+
+var (
+	httpRequests = newCounterVec("http_requests_total",
+		"Jumlah request HTTP per route, method dan status.", "route", "method", "status")
+	httpDuration = newHistogramVec("http_request_duration_seconds",
+		"Latensi request HTTP per route.", durationBuckets, "route")
+	searchRequests = newCounterVec("search_requests_total",
+		"Jumlah pencarian resep per algoritma.", "algorithm")
+	searchDuration = newHistogramVec("search_duration_seconds",
+		"Durasi pencarian resep per algoritma.", durationBuckets, "algorithm")
+	searchNodes = newHistogramVec("search_nodes_visited",
+		"Jumlah node yang dikunjungi per pencarian.", nodesBuckets, "algorithm")
+	searchDepth = newHistogramVec("search_max_depth",
+		"Kedalaman maksimum tree yang dijelajahi per pencarian.", depthBuckets, "algorithm")
+	searchFrontier = newHistogramVec("search_peak_frontier",
+		"Ukuran frontier terbesar per pencarian.", nodesBuckets, "algorithm")
+	searchPruned = newCounterVec("search_pruned_total",
+		"Jumlah resep yang dipangkas per algoritma dan alasan.", "algorithm", "reason")
+	searchGoroutines = newCounterVec("search_goroutines_spawned_total",
+		"Jumlah goroutine yang dibuat pencarian per algoritma.", "algorithm")
+	searchAllocated = newCounterVec("search_allocated_bytes_total",
+		"Perkiraan byte yang dialokasikan pencarian per algoritma.", "algorithm")
+	cacheRequests = newCounterVec("response_cache_requests_total",
+		"Jumlah request pencarian per hasil cache (hit, miss, not_modified, bypass).", "result")
+
+	responseCache *cache.Cache
+	searchSlots   *limit.Semaphore
+
+	lastScrapeMu sync.Mutex
+	lastScrape   time.Time
+)
+
+type counterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+type histogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mu   sync.Mutex
+	data map[string]*histogram
+}
+
+func newCounterVec(name, help string, labels ...string) *counterVec {
+	return &counterVec{name: name, help: help, labels: labels, values: make(map[string]float64)}
+}
+
+func newHistogramVec(name, help string, buckets []float64, labels ...string) *histogramVec {
+	return &histogramVec{name: name, help: help, labels: labels, buckets: buckets, data: make(map[string]*histogram)}
+}
+
+// Nilai label digabung jadi satu key map
+func labelKey(values []string) string {
+	return strings.Join(values, "\x00")
+}
+
+func (c *counterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *counterVec) Add(v float64, values ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[labelKey(values)] += v
+}
+
+func (h *histogramVec) Observe(v float64, values ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := labelKey(values)
+	hist, exists := h.data[key]
+	if !exists {
+		hist = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.data[key] = hist
+	}
+	for i, upper := range h.buckets {
+		if v <= upper {
+			hist.counts[i]++
+		}
+	}
+	hist.sum += v
+	hist.count++
+}
+
+func escapeLabel(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, "\n", `\n`)
+	return strings.ReplaceAll(v, `"`, `\"`)
+}
+
+func formatLabels(names []string, key string, extra ...string) string {
+	var values []string
+	if len(names) > 0 {
+		values = strings.Split(key, "\x00")
+	}
+
+	var parts []string
+	for i, name := range names {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, name, escapeLabel(values[i])))
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, extra[i], escapeLabel(extra[i+1])))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (c *counterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labels, key), formatFloat(c.values[key]))
+	}
+}
+
+func (h *histogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for _, key := range sortedKeys(h.data) {
+		hist := h.data[key]
+		for i, upper := range h.buckets {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, key, "le", formatFloat(upper)), hist.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, key, "le", "+Inf"), hist.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labels, key), formatFloat(hist.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labels, key), hist.count)
+	}
+}
+
+func writeGauge(w io.Writer, name, help string, samples map[string]float64, label string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	for _, key := range sortedKeys(samples) {
+		labels := ""
+		if label != "" {
+			labels = formatLabels([]string{label}, key)
+		}
+		fmt.Fprintf(w, "%s%s %s\n", name, labels, formatFloat(samples[key]))
+	}
+}
+
+func ObserveRequest(route string, method string, status int, duration time.Duration) {
+	httpRequests.Inc(route, method, strconv.Itoa(status))
+	httpDuration.Observe(duration.Seconds(), route)
+}
+
+func ObserveSearch(algorithm string, result Element.MetricsResult, duration time.Duration) {
+	searchRequests.Inc(algorithm)
+	searchDuration.Observe(duration.Seconds(), algorithm)
+	searchNodes.Observe(float64(result.NodesVisited), algorithm)
+	searchDepth.Observe(float64(result.MaxDepth), algorithm)
+	searchFrontier.Observe(float64(result.PeakFrontier), algorithm)
+	searchPruned.Add(float64(result.PrunedByTier), algorithm, "tier")
+	searchPruned.Add(float64(result.PrunedByVisited), algorithm, "visited")
+	searchPruned.Add(float64(result.PrunedByConstraint), algorithm, "constraint")
+	searchGoroutines.Add(float64(result.GoroutinesSpawned), algorithm)
+	searchAllocated.Add(float64(result.BytesAllocated), algorithm)
+}
+
+func ObserveCache(result string) {
+	cacheRequests.Inc(result)
+}
+
+// Cache yang ukurannya ikut diekspor
+func SetResponseCache(c *cache.Cache) {
+	responseCache = c
+}
+
+// Slot pencarian yang pemakaian dan antriannya ikut diekspor
+func SetSearchSlots(s *limit.Semaphore) {
+	searchSlots = s
+}
+
+func SetLastScrape(t time.Time) {
+	lastScrapeMu.Lock()
+	defer lastScrapeMu.Unlock()
+	lastScrape = t
+}
+
+func WritePrometheus(w io.Writer) {
+	httpRequests.write(w)
+	httpDuration.write(w)
+	searchRequests.write(w)
+	searchDuration.write(w)
+	searchNodes.write(w)
+	searchDepth.write(w)
+	searchFrontier.write(w)
+	searchPruned.write(w)
+	searchGoroutines.write(w)
+	searchAllocated.write(w)
+	cacheRequests.write(w)
+
+	writeGauge(w, "go_goroutines", "Jumlah goroutine yang sedang jalan.",
+		map[string]float64{"": float64(runtime.NumGoroutine())}, "")
+
+	elements := make(map[string]float64)
+	recipes := make(map[string]float64)
+	for _, ds := range Element.ListDatasets() {
+		stats := ds.Stats()
+		elements[ds.Name] = float64(stats.Elements)
+		recipes[ds.Name] = float64(stats.Recipes)
+	}
+	writeGauge(w, "dataset_elements", "Jumlah elemen per dataset.", elements, "dataset")
+	writeGauge(w, "dataset_recipes", "Jumlah resep per dataset.", recipes, "dataset")
+
+	cacheStats := responseCache.Stats()
+	writeGauge(w, "response_cache_entries", "Jumlah response pencarian di cache.",
+		map[string]float64{"": float64(cacheStats.Entries)}, "")
+	writeGauge(w, "response_cache_bytes", "Total ukuran body response di cache.",
+		map[string]float64{"": float64(cacheStats.Bytes)}, "")
+
+	writeGauge(w, "search_slots_in_use", "Jumlah slot pencarian yang sedang dipakai.",
+		map[string]float64{"": float64(searchSlots.InFlight())}, "")
+	writeGauge(w, "search_queue_waiting", "Jumlah pencarian yang antri menunggu slot.",
+		map[string]float64{"": float64(searchSlots.Waiting())}, "")
+
+	lastScrapeMu.Lock()
+	scraped := lastScrape
+	lastScrapeMu.Unlock()
+	lastScrapeValue := 0.0
+	if !scraped.IsZero() {
+		lastScrapeValue = float64(scraped.Unix())
+	}
+	writeGauge(w, "last_successful_scrape_timestamp_seconds", "Waktu scrape terakhir yang berhasil (unix).",
+		map[string]float64{"": lastScrapeValue}, "")
+}
+
+func Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	WritePrometheus(w)
+}
+
+// Middleware pencatat jumlah request dan latensi per route
+func Instrument(route string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		// Di belakang logging.RequestID writer-nya udah recorder, ga dibungkus dua kali
+		recorder := logging.NewStatusRecorder(w)
+		next.ServeHTTP(recorder, r)
+		ObserveRequest(route, r.Method, recorder.Status, time.Since(start))
+	}
+}
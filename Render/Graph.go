@@ -0,0 +1,92 @@
+package render
+
+import (
+	"fmt"
+	"stima-2-be/Element"
+	"strings"
+)
+
+func nodeLabel(e Element.Element) string {
+	return fmt.Sprintf("%s (Tier %s)", e.Root, e.Tier)
+}
+
+func escapeDOT(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
+
+func escapeMermaid(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+// Graphviz DOT, tiap tree jadi satu cluster
+func DOT(trees []Element.Tree) string {
+	var sb strings.Builder
+	sb.WriteString("digraph recipes {\n")
+	sb.WriteString("  rankdir=TB;\n")
+	sb.WriteString("  node [shape=box, style=rounded];\n")
+
+	counter := 0
+	for i, tree := range trees {
+		fmt.Fprintf(&sb, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&sb, "    label=\"Recipe %d\";\n", i+1)
+		writeDOTNode(&sb, tree, &counter)
+		sb.WriteString("  }\n")
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func writeDOTNode(sb *strings.Builder, t Element.Tree, counter *int) string {
+	id := fmt.Sprintf("n%d", *counter)
+	*counter++
+
+	fmt.Fprintf(sb, "    %s [label=\"%s\"];\n", id, escapeDOT(nodeLabel(t.Root)))
+	for _, child := range t.Children {
+		childID := writeDOTNode(sb, child, counter)
+		fmt.Fprintf(sb, "    %s -> %s;\n", id, childID)
+	}
+	return id
+}
+
+// Mermaid flowchart, tiap tree jadi satu subgraph
+func Mermaid(trees []Element.Tree) string {
+	var sb strings.Builder
+	sb.WriteString("graph TD\n")
+
+	counter := 0
+	for i, tree := range trees {
+		fmt.Fprintf(&sb, "  subgraph recipe%d[\"Recipe %d\"]\n", i+1, i+1)
+		writeMermaidNode(&sb, tree, &counter)
+		sb.WriteString("  end\n")
+	}
+
+	return sb.String()
+}
+
+func writeMermaidNode(sb *strings.Builder, t Element.Tree, counter *int) string {
+	id := fmt.Sprintf("n%d", *counter)
+	*counter++
+
+	fmt.Fprintf(sb, "    %s[\"%s\"]\n", id, escapeMermaid(nodeLabel(t.Root)))
+	for _, child := range t.Children {
+		childID := writeMermaidNode(sb, child, counter)
+		fmt.Fprintf(sb, "    %s --> %s\n", id, childID)
+	}
+	return id
+}
+
+// Tree versi teks berindentasi, format sama seperti bfs.PrintTree tapi ditulis ke string
+func TreeText(t Element.Tree, indent string) string {
+	var sb strings.Builder
+	writeTreeText(&sb, t, indent)
+	return sb.String()
+}
+
+func writeTreeText(sb *strings.Builder, t Element.Tree, indent string) {
+	fmt.Fprintf(sb, "%s%s (Tier: %s)\n", indent, t.Root.Root, t.Root.Tier)
+	for _, child := range t.Children {
+		writeTreeText(sb, child, indent+"  ")
+	}
+}
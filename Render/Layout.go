@@ -0,0 +1,131 @@
+package render
+
+import "stima-2-be/Element"
+
+// Jarak minimum antar node yang sekedalaman (satuan slot)
+const nodeSeparation = 1.0
+
+// Posisi satu node tree hasil tidy-tree layout, X dalam satuan slot, Y = kedalaman
+type LayoutNode struct {
+	Root     Element.Element `json:"Root"`
+	Children []LayoutNode    `json:"Children"`
+	X        float64         `json:"x"`
+	Y        float64         `json:"y"`
+	Width    float64         `json:"width"`
+	Depth    int             `json:"depth"`
+
+	offset float64
+}
+
+// Kontur kiri dan kanan subtree per kedalaman, relatif ke root subtree
+type contour struct {
+	left  []float64
+	right []float64
+}
+
+// Layout Reingold-Tilford: subtree saudara didempetin sedekat mungkin tanpa tumpang tindih,
+// parent di tengah anak pertama dan terakhir. Hasilnya deterministik untuk tree yang sama.
+func Layout(t Element.Tree) LayoutNode {
+	root, c := layoutSubtree(t, 0)
+
+	minX := 0.0
+	for _, x := range c.left {
+		if x < minX {
+			minX = x
+		}
+	}
+	place(&root, -minX)
+	return root
+}
+
+func layoutSubtree(t Element.Tree, depth int) (LayoutNode, contour) {
+	node := LayoutNode{
+		Root:  t.Root,
+		Y:     float64(depth),
+		Depth: depth,
+	}
+
+	var acc contour
+	for i, child := range t.Children {
+		childNode, childContour := layoutSubtree(child, depth+1)
+
+		shift := 0.0
+		if i > 0 {
+			// Geser ke kanan sampai kontur kiri anak ini ga nabrak kontur kanan saudara sebelumnya
+			shift = acc.right[0] - childContour.left[0] + nodeSeparation
+			for k := 1; k < len(acc.right) && k < len(childContour.left); k++ {
+				if s := acc.right[k] - childContour.left[k] + nodeSeparation; s > shift {
+					shift = s
+				}
+			}
+		}
+		childNode.offset = shift
+
+		for k := range childContour.left {
+			l := childContour.left[k] + shift
+			r := childContour.right[k] + shift
+			if k < len(acc.left) {
+				acc.right[k] = r
+			} else {
+				acc.left = append(acc.left, l)
+				acc.right = append(acc.right, r)
+			}
+		}
+		node.Children = append(node.Children, childNode)
+	}
+
+	c := contour{left: []float64{0}, right: []float64{0}}
+	if len(node.Children) > 0 {
+		mid := (node.Children[0].offset + node.Children[len(node.Children)-1].offset) / 2
+		for i := range node.Children {
+			node.Children[i].offset -= mid
+		}
+		for k := range acc.left {
+			c.left = append(c.left, acc.left[k]-mid)
+			c.right = append(c.right, acc.right[k]-mid)
+		}
+	}
+
+	minX, maxX := 0.0, 0.0
+	for k := range c.left {
+		if c.left[k] < minX {
+			minX = c.left[k]
+		}
+		if c.right[k] > maxX {
+			maxX = c.right[k]
+		}
+	}
+	node.Width = maxX - minX + 1
+	return node, c
+}
+
+// Ubah offset relatif jadi koordinat absolut
+func place(n *LayoutNode, x float64) {
+	n.X = x
+	for i := range n.Children {
+		place(&n.Children[i], x+n.Children[i].offset)
+	}
+}
+
+func LayoutAll(trees []Element.Tree) []LayoutNode {
+	result := []LayoutNode{}
+	for _, t := range trees {
+		result = append(result, Layout(t))
+	}
+	return result
+}
+
+// Lebar dan tinggi layout dalam satuan slot
+func LayoutBounds(n LayoutNode) (float64, int) {
+	maxX, maxDepth := n.X, n.Depth
+	for _, child := range n.Children {
+		x, d := LayoutBounds(child)
+		if x > maxX {
+			maxX = x
+		}
+		if d > maxDepth {
+			maxDepth = d
+		}
+	}
+	return maxX, maxDepth
+}
@@ -0,0 +1,92 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"stima-2-be/Element"
+	"strings"
+)
+
+const (
+	boxWidth  = 140.0
+	boxHeight = 40.0
+	gapX      = 20.0
+	gapY      = 50.0
+	margin    = 20.0
+)
+
+// Warna kotak berdasarkan tier
+var tierColors = []string{
+	"#d9f0d3", "#c6dbef", "#fdd0a2", "#dadaeb", "#fcbba1",
+	"#c7e9c0", "#9ecae1", "#fdae6b", "#bcbddc", "#fc9272",
+}
+
+func tierColor(tier string) string {
+	t := Element.ParseTier(tier)
+	if t < 0 {
+		t = 0
+	}
+	return tierColors[t%len(tierColors)]
+}
+
+func boxPosition(n LayoutNode) (float64, float64) {
+	x := margin + n.X*(boxWidth+gapX)
+	y := margin + n.Y*(boxHeight+gapY)
+	return x, y
+}
+
+// Render satu recipe tree jadi gambar SVG
+func SVG(t Element.Tree) string {
+	root := Layout(t)
+	maxX, maxDepth := LayoutBounds(root)
+	width := 2*margin + (maxX+1)*(boxWidth+gapX) - gapX
+	height := 2*margin + float64(maxDepth+1)*(boxHeight+gapY) - gapY
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="sans-serif">`+"\n",
+		width, height, width, height)
+	sb.WriteString(`<rect width="100%" height="100%" fill="#ffffff"/>` + "\n")
+	writeSVGEdges(&sb, root)
+	writeSVGNodes(&sb, root)
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+// Garis kombinasi: parent turun ke titik tengah lalu cabang ke tiap bahan
+func writeSVGEdges(sb *strings.Builder, n LayoutNode) {
+	if len(n.Children) == 0 {
+		return
+	}
+
+	px, py := boxPosition(n)
+	cx := px + boxWidth/2
+	junctionY := py + boxHeight + gapY/2
+
+	fmt.Fprintf(sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#555" stroke-width="1.5"/>`+"\n",
+		cx, py+boxHeight, cx, junctionY)
+	for _, child := range n.Children {
+		chx, chy := boxPosition(child)
+		ccx := chx + boxWidth/2
+		fmt.Fprintf(sb, `<polyline points="%.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="none" stroke="#555" stroke-width="1.5"/>`+"\n",
+			cx, junctionY, ccx, junctionY, ccx, chy)
+		writeSVGEdges(sb, child)
+	}
+	if len(n.Children) > 1 {
+		fmt.Fprintf(sb, `<circle cx="%.1f" cy="%.1f" r="8" fill="#ffffff" stroke="#555"/>`+"\n", cx, junctionY)
+		fmt.Fprintf(sb, `<text x="%.1f" y="%.1f" font-size="12" text-anchor="middle">+</text>`+"\n", cx, junctionY+4)
+	}
+}
+
+func writeSVGNodes(sb *strings.Builder, n LayoutNode) {
+	x, y := boxPosition(n)
+	fmt.Fprintf(sb, `<rect x="%.1f" y="%.1f" width="%.0f" height="%.0f" rx="6" fill="%s" stroke="#333"/>`+"\n",
+		x, y, boxWidth, boxHeight, tierColor(n.Root.Tier))
+	fmt.Fprintf(sb, `<text x="%.1f" y="%.1f" font-size="13" text-anchor="middle">%s</text>`+"\n",
+		x+boxWidth/2, y+17, html.EscapeString(n.Root.Root))
+	fmt.Fprintf(sb, `<text x="%.1f" y="%.1f" font-size="10" text-anchor="middle" fill="#555">Tier %s</text>`+"\n",
+		x+boxWidth/2, y+32, html.EscapeString(n.Root.Tier))
+
+	for _, child := range n.Children {
+		writeSVGNodes(sb, child)
+	}
+}
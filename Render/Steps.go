@@ -0,0 +1,89 @@
+package render
+
+import (
+	"fmt"
+	"stima-2-be/Element"
+	"strings"
+)
+
+// Satu langkah crafting: Left + Right → Result
+type Step struct {
+	Number int    `json:"step"`
+	Left   string `json:"left"`
+	Right  string `json:"right"`
+	Result string `json:"result"`
+}
+
+type RecipeSteps struct {
+	Recipe int    `json:"recipe"`
+	Steps  []Step `json:"steps"`
+}
+
+// Ubah tree jadi urutan langkah (post-order), tiap elemen antara cukup dibuat sekali
+func Steps(t Element.Tree) []Step {
+	steps := []Step{}
+	crafted := make(map[string]bool)
+	collectSteps(t, crafted, &steps)
+	return steps
+}
+
+func collectSteps(t Element.Tree, crafted map[string]bool, steps *[]Step) {
+	// Elemen yang udah pernah dibuat ga perlu dibuat ulang beserta bahannya
+	key := strings.ToLower(t.Root.Root)
+	if len(t.Children) == 0 || crafted[key] {
+		return
+	}
+
+	for _, child := range t.Children {
+		collectSteps(child, crafted, steps)
+	}
+	crafted[key] = true
+
+	*steps = append(*steps, Step{
+		Number: len(*steps) + 1,
+		Left:   t.Root.Left,
+		Right:  t.Root.Right,
+		Result: t.Root.Root,
+	})
+}
+
+func AllSteps(trees []Element.Tree) []RecipeSteps {
+	result := []RecipeSteps{}
+	for i, t := range trees {
+		result = append(result, RecipeSteps{
+			Recipe: i + 1,
+			Steps:  Steps(t),
+		})
+	}
+	return result
+}
+
+// Versi teks: "1. Water + Fire → Steam"
+func StepsText(steps []Step) string {
+	var sb strings.Builder
+	for _, s := range steps {
+		fmt.Fprintf(&sb, "%d. %s + %s → %s\n", s.Number, s.Left, s.Right, s.Result)
+	}
+	return sb.String()
+}
+
+func AllStepsText(trees []Element.Tree) string {
+	var sb strings.Builder
+	for i, t := range trees {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "Recipe %d:\n", i+1)
+		sb.WriteString(StepsText(Steps(t)))
+	}
+	return sb.String()
+}
+
+// Versi teks rencana gabungan: "1. Water + Fire → Steam (untuk Cloud, Geyser)", tiap baris diawali indent
+func PlanText(plan Element.CombinedPlan, indent string) string {
+	var sb strings.Builder
+	for _, s := range plan.Steps {
+		fmt.Fprintf(&sb, "%s%d. %s + %s → %s (untuk %s)\n", indent, s.Number, s.Left, s.Right, s.Result, strings.Join(s.For, ", "))
+	}
+	return sb.String()
+}
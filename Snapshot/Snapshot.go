@@ -0,0 +1,345 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"stima-2-be/Element"
+	"sync"
+	"time"
+)
+
+const indexFile = "index.json"
+
+// Folder snapshot dan jumlah snapshot yang disimpan, bisa diganti lewat config
+var (
+	DataDir      = "data/snapshots"
+	MaxSnapshots = 10
+)
+
+var ErrNotFound = errors.New("snapshot tidak ditemukan")
+
+// Metadata satu snapshot hasil scrape
+type Meta struct {
+	Version   string    `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Checksum  string    `json:"checksum"`
+	Elements  int       `json:"elements"`
+}
+
+type index struct {
+	Active    string `json:"active"`
+	Snapshots []Meta `json:"snapshots"`
+}
+
+var (
+	mu     sync.Mutex
+	active Meta
+)
+
+// Status load dataset buat readiness, dikunci terpisah dari mu
+// supaya bisa dibaca selama reload berjalan
+type Status struct {
+	Active       Meta      `json:"active"`
+	Reloading    bool      `json:"reloading"`
+	LastLoadedAt time.Time `json:"last_loaded_at"`
+	LastError    string    `json:"last_error,omitempty"`
+	LastErrorAt  time.Time `json:"last_error_at"`
+}
+
+var (
+	statusMu  sync.Mutex
+	status    Status
+	reloading int
+)
+
+func snapshotPath(version string) string {
+	return filepath.Join(DataDir, version+".json")
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func readIndex() (index, error) {
+	var idx index
+	data, err := os.ReadFile(filepath.Join(DataDir, indexFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return idx, err
+	}
+	err = json.Unmarshal(data, &idx)
+	return idx, err
+}
+
+func writeIndex(idx index) error {
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return Element.WriteFileAtomic(filepath.Join(DataDir, indexFile), data, 0644)
+}
+
+func findMeta(idx index, version string) (Meta, bool) {
+	for _, m := range idx.Snapshots {
+		if m.Version == version {
+			return m, true
+		}
+	}
+	return Meta{}, false
+}
+
+// Simpan hasil scrape sebagai snapshot baru (belum diaktifkan)
+func Save(elements []Element.Element) (Meta, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := os.MkdirAll(DataDir, 0755); err != nil {
+		return Meta{}, err
+	}
+
+	idx, err := readIndex()
+	if err != nil {
+		return Meta{}, err
+	}
+
+	data, err := json.MarshalIndent(elements, "", "  ")
+	if err != nil {
+		return Meta{}, err
+	}
+
+	now := time.Now().UTC()
+	version := now.Format("20060102T150405.000000Z")
+	for i := 2; ; i++ {
+		if _, exists := findMeta(idx, version); !exists {
+			break
+		}
+		version = fmt.Sprintf("%s-%d", now.Format("20060102T150405.000000Z"), i)
+	}
+
+	meta := Meta{
+		Version:   version,
+		CreatedAt: now,
+		Checksum:  checksum(data),
+		Elements:  len(elements),
+	}
+	if err := Element.WriteFileAtomic(snapshotPath(version), data, 0644); err != nil {
+		return Meta{}, err
+	}
+
+	idx.Snapshots = append(idx.Snapshots, meta)
+	idx, removed := prune(idx, MaxSnapshots)
+	if err := writeIndex(idx); err != nil {
+		return Meta{}, err
+	}
+	// File lama baru dihapus setelah index baru tersimpan
+	for _, version := range removed {
+		os.Remove(snapshotPath(version))
+	}
+	return meta, nil
+}
+
+// Buang snapshot paling lama kalau lebih dari max, yang aktif ga dibuang.
+// Balikin versi yang dibuang dari index
+func prune(idx index, max int) (index, []string) {
+	var removed []string
+	sort.Slice(idx.Snapshots, func(i, j int) bool {
+		return idx.Snapshots[i].CreatedAt.Before(idx.Snapshots[j].CreatedAt)
+	})
+
+	for len(idx.Snapshots) > max {
+		victim := 0
+		if idx.Snapshots[victim].Version == idx.Active {
+			victim = 1
+		}
+		removed = append(removed, idx.Snapshots[victim].Version)
+		idx.Snapshots = append(idx.Snapshots[:victim], idx.Snapshots[victim+1:]...)
+	}
+	return idx, removed
+}
+
+// Baca isi snapshot dan cek checksum-nya
+func Load(version string) ([]Element.Element, Meta, error) {
+	idx, err := readIndex()
+	if err != nil {
+		return nil, Meta{}, err
+	}
+	meta, exists := findMeta(idx, version)
+	if !exists {
+		return nil, Meta{}, ErrNotFound
+	}
+
+	data, err := os.ReadFile(snapshotPath(version))
+	if err != nil {
+		return nil, meta, err
+	}
+	if checksum(data) != meta.Checksum {
+		return nil, meta, fmt.Errorf("checksum snapshot %s tidak cocok", version)
+	}
+
+	var elements []Element.Element
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, meta, err
+	}
+	return elements, meta, nil
+}
+
+func List() ([]Meta, string, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	idx, err := readIndex()
+	if err != nil {
+		return nil, "", err
+	}
+	sort.Slice(idx.Snapshots, func(i, j int) bool {
+		return idx.Snapshots[i].CreatedAt.After(idx.Snapshots[j].CreatedAt)
+	})
+	return idx.Snapshots, idx.Active, nil
+}
+
+// Aktifkan snapshot, dataset yang dipake search langsung diganti
+func Activate(version string) (Meta, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return activate(version)
+}
+
+func activate(version string) (Meta, error) {
+	done := BeginReload()
+	defer done()
+
+	elements, meta, err := Load(version)
+	if err != nil {
+		RecordError(err)
+		return Meta{}, err
+	}
+
+	idx, err := readIndex()
+	if err != nil {
+		RecordError(err)
+		return Meta{}, err
+	}
+	idx.Active = version
+	if err := writeIndex(idx); err != nil {
+		RecordError(err)
+		return Meta{}, err
+	}
+
+	Element.SetElements(elements, meta.Version)
+	active = meta
+	recordLoaded(meta)
+	return meta, nil
+}
+
+// Tandai reload (scrape/activate) lagi jalan, panggil fungsi hasilnya kalau selesai
+func BeginReload() func() {
+	statusMu.Lock()
+	reloading++
+	status.Reloading = true
+	statusMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			statusMu.Lock()
+			reloading--
+			status.Reloading = reloading > 0
+			statusMu.Unlock()
+		})
+	}
+}
+
+func recordLoaded(meta Meta) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	status.Active = meta
+	status.LastLoadedAt = time.Now().UTC()
+	status.LastError = ""
+	status.LastErrorAt = time.Time{}
+}
+
+// Catat error reload terakhir buat /readyz, dipakai juga kalau scrape gagal sebelum sempat load
+func RecordError(err error) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	status.LastError = err.Error()
+	status.LastErrorAt = time.Now().UTC()
+}
+
+// Status load terakhir, aman dipanggil kapan aja
+func CurrentStatus() Status {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	return status
+}
+
+// Balik ke snapshot sebelum yang lagi aktif
+func Rollback() (Meta, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	idx, err := readIndex()
+	if err != nil {
+		return Meta{}, err
+	}
+	sort.Slice(idx.Snapshots, func(i, j int) bool {
+		return idx.Snapshots[i].CreatedAt.Before(idx.Snapshots[j].CreatedAt)
+	})
+
+	for i, m := range idx.Snapshots {
+		if m.Version == idx.Active {
+			if i == 0 {
+				return Meta{}, errors.New("tidak ada snapshot sebelumnya")
+			}
+			return activate(idx.Snapshots[i-1].Version)
+		}
+	}
+	return Meta{}, ErrNotFound
+}
+
+// Load snapshot aktif terakhir, dipanggil waktu server start
+func LoadActive() (Meta, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	idx, err := readIndex()
+	if err != nil {
+		RecordError(err)
+		return Meta{}, err
+	}
+	if idx.Active == "" {
+		return Meta{}, ErrNotFound
+	}
+	return activate(idx.Active)
+}
+
+// Baca isi snapshot aktif tanpa mengaktifkannya, dipakai CLI
+func ReadActive() ([]Element.Element, Meta, error) {
+	idx, err := readIndex()
+	if err != nil {
+		return nil, Meta{}, err
+	}
+	if idx.Active == "" {
+		return nil, Meta{}, ErrNotFound
+	}
+	return Load(idx.Active)
+}
+
+func Active() Meta {
+	mu.Lock()
+	defer mu.Unlock()
+	return active
+}
+
+func ActiveVersion() string {
+	return Active().Version
+}
@@ -0,0 +1,61 @@
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func snapshotIndex(active string, versions ...string) index {
+	idx := index{Active: active}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, v := range versions {
+		idx.Snapshots = append(idx.Snapshots, Meta{Version: v, CreatedAt: start.Add(time.Duration(i) * time.Hour)})
+	}
+	return idx
+}
+
+func TestPrune(t *testing.T) {
+	tests := []struct {
+		name        string
+		idx         index
+		max         int
+		wantRemoved []string
+		wantKept    []string
+	}{
+		{"masih muat", snapshotIndex("b", "a", "b"), 3, nil, []string{"a", "b"}},
+		{"buang paling lama", snapshotIndex("d", "a", "b", "c", "d"), 2, []string{"a", "b"}, []string{"c", "d"}},
+		{"yang aktif ga dibuang", snapshotIndex("a", "a", "b", "c"), 2, []string{"b"}, []string{"a", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx, removed := prune(tt.idx, tt.max)
+			var kept []string
+			for _, m := range idx.Snapshots {
+				kept = append(kept, m.Version)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) || !reflect.DeepEqual(kept, tt.wantKept) {
+				t.Errorf("prune = (%v dibuang, %v sisa), mau (%v, %v)", removed, kept, tt.wantRemoved, tt.wantKept)
+			}
+		})
+	}
+}
+
+func TestSaveKeepsMaxSnapshots(t *testing.T) {
+	oldDir, oldMax := DataDir, MaxSnapshots
+	DataDir, MaxSnapshots = t.TempDir(), 2
+	defer func() { DataDir, MaxSnapshots = oldDir, oldMax }()
+
+	for i := 0; i < 4; i++ {
+		if _, err := Save(nil); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	metas, _, err := List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(metas) != 2 {
+		t.Errorf("jumlah snapshot = %d, mau 2", len(metas))
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Fungsi completion: balikin posisi awal fragmen yang diganti dan kandidat penggantinya
+type completer func(line string) (int, []string)
+
+// Line editor minimal: ketik, backspace, Tab completion, history panah atas/bawah
+type lineEditor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	complete completer
+	history  []string
+}
+
+func (e *lineEditor) redraw(prompt string, line []rune) {
+	fmt.Fprintf(e.out, "\r\033[K%s%s", prompt, string(line))
+}
+
+func (e *lineEditor) ReadLine(prompt string) (string, error) {
+	var line []rune
+	historyPos := len(e.history)
+	lastWasTab := false
+
+	fmt.Fprint(e.out, prompt)
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		isTab := r == '\t'
+		switch {
+		case r == '\r' || r == '\n':
+			fmt.Fprint(e.out, "\n")
+			result := string(line)
+			if strings.TrimSpace(result) != "" {
+				e.history = append(e.history, result)
+			}
+			return result, nil
+		case r == 3: // Ctrl-C
+			fmt.Fprint(e.out, "^C\n")
+			return "", nil
+		case r == 4: // Ctrl-D
+			if len(line) == 0 {
+				fmt.Fprint(e.out, "\n")
+				return "", io.EOF
+			}
+		case r == 127 || r == 8:
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+				e.redraw(prompt, line)
+			}
+		case isTab:
+			line = e.completeLine(prompt, line, lastWasTab)
+		case r == 27: // escape sequence, cuma panah atas/bawah yang dipake
+			next, _, _ := e.in.ReadRune()
+			if next != '[' {
+				continue
+			}
+			code, _, _ := e.in.ReadRune()
+			switch {
+			case code == 'A' && historyPos > 0:
+				historyPos--
+				line = []rune(e.history[historyPos])
+			case code == 'B' && historyPos < len(e.history):
+				historyPos++
+				line = nil
+				if historyPos < len(e.history) {
+					line = []rune(e.history[historyPos])
+				}
+			}
+			e.redraw(prompt, line)
+		case r >= 32:
+			line = append(line, r)
+			fmt.Fprint(e.out, string(r))
+		}
+		lastWasTab = isTab
+	}
+}
+
+func (e *lineEditor) completeLine(prompt string, line []rune, listAll bool) []rune {
+	if e.complete == nil {
+		return line
+	}
+	text := string(line)
+	start, candidates := e.complete(text)
+	if len(candidates) == 0 {
+		return line
+	}
+
+	if len(candidates) == 1 {
+		line = []rune(text[:start] + candidates[0] + " ")
+		e.redraw(prompt, line)
+		return line
+	}
+
+	prefix := commonPrefix(candidates)
+	if len([]rune(prefix)) > len([]rune(text[start:])) {
+		line = []rune(text[:start] + prefix)
+		e.redraw(prompt, line)
+		return line
+	}
+
+	// Tab kedua kali: tampilin semua kandidat
+	if listAll {
+		sort.Strings(candidates)
+		fmt.Fprintf(e.out, "\n%s\n", strings.Join(candidates, "   "))
+		e.redraw(prompt, line)
+	}
+	return line
+}
+
+// Prefix bersama (case-insensitive), hurufnya ngikut kandidat pertama
+func commonPrefix(items []string) string {
+	prefix := []rune(items[0])
+	for _, item := range items[1:] {
+		r := []rune(item)
+		n := 0
+		for n < len(prefix) && n < len(r) && strings.EqualFold(string(prefix[n]), string(r[n])) {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
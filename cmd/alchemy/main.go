@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	bfs "stima-2-be/BFS"
+	dfs "stima-2-be/DFS"
+	"stima-2-be/Element"
+	render "stima-2-be/Render"
+	snapshot "stima-2-be/Snapshot"
+	"strings"
+)
+
+type options struct {
+	data      string
+	snapshots string
+	element   string
+	count     int
+	algorithm string
+	format    string
+	metrics   bool
+	repl      bool
+	exclude   string
+	require   string
+	maxDepth  int
+	maxNodes  int
+}
+
+func parseFlags() options {
+	var opts options
+	flag.StringVar(&opts.data, "data", "", "file dataset (array elemen atau file dataset dengan base_components), kosong = snapshot aktif")
+	flag.StringVar(&opts.snapshots, "snapshots", snapshot.DataDir, "folder snapshot hasil scrape, dipakai kalau -data kosong")
+	flag.StringVar(&opts.element, "element", "", "elemen yang dicari resepnya")
+	flag.IntVar(&opts.count, "count", 1, "jumlah resep yang dicari")
+	flag.StringVar(&opts.algorithm, "algorithm", "bfs", "algoritma: bfs atau dfs")
+	flag.StringVar(&opts.format, "format", "tree", "format output: tree, steps, dot, mermaid, json")
+	flag.BoolVar(&opts.metrics, "metrics", false, "tampilkan metrics pencarian")
+	flag.BoolVar(&opts.repl, "i", false, "mode interaktif (REPL)")
+	flag.StringVar(&opts.exclude, "exclude", "", "elemen yang tidak boleh ada di tree, dipisah koma")
+	flag.StringVar(&opts.require, "require", "", "elemen yang wajib ada di tree, dipisah koma")
+	flag.IntVar(&opts.maxDepth, "max-depth", 0, "kedalaman tree maksimum (0 = tanpa batas)")
+	flag.IntVar(&opts.maxNodes, "max-nodes", 0, "jumlah node maksimum per tree (0 = tanpa batas)")
+	flag.Parse()
+	return opts
+}
+
+// Tanpa file pakai snapshot aktif, sama seperti yang dilayani server
+func loadActiveSnapshot(dir string) (*Element.Dataset, error) {
+	snapshot.DataDir = dir
+	elements, meta, err := snapshot.ReadActive()
+	if errors.Is(err, snapshot.ErrNotFound) {
+		return nil, fmt.Errorf("belum ada snapshot aktif di %s, jalankan scrape dulu atau pakai -data", dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Element.NewDataset(Element.DefaultDataset, meta.Version, elements, Element.BaseComponents), nil
+}
+
+// File bisa berupa array elemen (isi snapshot) atau objek dataset
+func loadDataset(filename string) (*Element.Dataset, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var elements []Element.Element
+	if err := json.Unmarshal(data, &elements); err == nil {
+		return Element.NewDataset(Element.DefaultDataset, filename, elements, Element.BaseComponents), nil
+	}
+	return Element.LoadDatasetFromFile(filename)
+}
+
+func search(algorithm string, name string, ds *Element.Dataset, count int, c *Element.Constraints) ([]Element.Tree, Element.MetricsResult, error) {
+	switch algorithm {
+	case "bfs":
+		trees, info := bfs.MultipleRecipeWithConstraints(name, ds, count, c)
+		return trees, info, nil
+	case "dfs":
+		trees, info := dfs.MultipleRecipeWithConstraints(name, ds, count, c)
+		return trees, info, nil
+	default:
+		return nil, Element.MetricsResult{}, fmt.Errorf("algoritma tidak didukung: %s (bfs, dfs)", algorithm)
+	}
+}
+
+func printResult(format string, trees []Element.Tree, info Element.MetricsResult) error {
+	switch format {
+	case "tree":
+		for i, t := range trees {
+			fmt.Printf("Recipe %d:\n", i+1)
+			fmt.Print(render.TreeText(t, "  "))
+		}
+	case "steps":
+		fmt.Print(render.AllStepsText(trees))
+	case "dot":
+		fmt.Print(render.DOT(trees))
+	case "mermaid":
+		fmt.Print(render.Mermaid(trees))
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode([]interface{}{info, trees})
+	default:
+		return fmt.Errorf("format tidak didukung: %s (tree, steps, dot, mermaid, json)", format)
+	}
+	return nil
+}
+
+func splitList(s string) []string {
+	result := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func fail(code int, format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "alchemy: "+format+"\n", args...)
+	os.Exit(code)
+}
+
+func main() {
+	opts := parseFlags()
+	if opts.element == "" && !opts.repl {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if opts.count < 1 {
+		fail(2, "count harus >= 1")
+	}
+	if opts.maxDepth < 0 || opts.maxNodes < 0 {
+		fail(2, "max-depth dan max-nodes harus >= 0")
+	}
+
+	var ds *Element.Dataset
+	var err error
+	if opts.data == "" {
+		if ds, err = loadActiveSnapshot(opts.snapshots); err != nil {
+			fail(1, "gagal load snapshot aktif: %v", err)
+		}
+	} else if ds, err = loadDataset(opts.data); err != nil {
+		fail(1, "gagal load dataset %s: %v", opts.data, err)
+	}
+
+	if opts.repl {
+		if err := runREPL(ds, strings.ToLower(opts.algorithm)); err != nil {
+			fail(1, "%v", err)
+		}
+		return
+	}
+	if !ds.HasElement(opts.element) {
+		suggestions := ds.Suggest(opts.element, 5)
+		if len(suggestions) > 0 {
+			fail(1, "elemen %q tidak ditemukan, mungkin maksudnya: %s", opts.element, strings.Join(suggestions, ", "))
+		}
+		fail(1, "elemen %q tidak ditemukan", opts.element)
+	}
+
+	exclude, require := splitList(opts.exclude), splitList(opts.require)
+	for _, name := range append(append([]string{}, exclude...), require...) {
+		if !ds.HasElement(name) {
+			fail(1, "elemen %q di exclude/require tidak ditemukan", name)
+		}
+	}
+	c := Element.NewConstraints(ds, exclude, require, opts.maxDepth, opts.maxNodes)
+
+	trees, info, err := search(strings.ToLower(opts.algorithm), opts.element, ds, opts.count, c)
+	if err != nil {
+		fail(2, "%v", err)
+	}
+	if err := printResult(opts.format, trees, info); err != nil {
+		fail(2, "%v", err)
+	}
+
+	if opts.metrics {
+		metrics, _ := json.Marshal(info)
+		fmt.Fprintf(os.Stderr, "metrics: %s\n", metrics)
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"stima-2-be/Element"
+	render "stima-2-be/Render"
+	"strconv"
+	"strings"
+)
+
+var replCommands = []string{"recipes", "usedin", "path", "common", "tier", "stats", "algo", "help", "quit"}
+
+const replHelp = `Perintah:
+  recipes <elemen> [n]   cari n resep (default 1) dengan algoritma aktif
+  usedin <elemen>        resep yang memakai elemen sebagai bahan
+  path <a> <b>           rantai resep terpendek dari a sampai b
+  common <a> <b>         elemen antara yang dipakai bareng a dan b + rencana crafting gabungan
+  tier <elemen>          tier elemen
+  stats                  ringkasan dataset
+  algo [bfs|dfs]         lihat/ganti algoritma
+  help                   tampilkan bantuan ini
+  quit                   keluar
+Nama elemen boleh mengandung spasi atau diapit tanda kutip. Tekan Tab untuk melengkapi nama.
+`
+
+type repl struct {
+	ds        *Element.Dataset
+	algorithm string
+	names     []string
+	out       io.Writer
+}
+
+// Pecah argumen per spasi, teks dalam tanda kutip dianggap satu argumen
+func splitArgs(line string) []string {
+	var args []string
+	var current strings.Builder
+	inQuote := false
+	hasToken := false
+
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			hasToken = true
+		case r == ' ' && !inQuote:
+			if hasToken {
+				args = append(args, current.String())
+				current.Reset()
+				hasToken = false
+			}
+		default:
+			current.WriteRune(r)
+			hasToken = true
+		}
+	}
+	if hasToken {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// Tab completion: perintah di kata pertama, nama elemen setelahnya
+func (r *repl) complete(line string) (int, []string) {
+	if !strings.Contains(line, " ") {
+		var result []string
+		for _, cmd := range replCommands {
+			if strings.HasPrefix(cmd, strings.ToLower(line)) {
+				result = append(result, cmd)
+			}
+		}
+		return 0, result
+	}
+
+	// Coba dari fragmen terpanjang dulu biar nama elemen yang ada spasinya ikut kena
+	start := strings.Index(line, " ") + 1
+	for start <= len(line) {
+		fragment := strings.ToLower(line[start:])
+		var result []string
+		for _, name := range r.names {
+			if strings.HasPrefix(strings.ToLower(name), fragment) {
+				result = append(result, name)
+			}
+		}
+		if len(result) > 0 {
+			return start, result
+		}
+
+		next := strings.Index(line[start:], " ")
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	return len(line), nil
+}
+
+// Gabungin argumen jadi nama elemen
+func (r *repl) element(args []string) (string, error) {
+	name := strings.Join(args, " ")
+	if name == "" {
+		return "", errors.New("nama elemen wajib diisi")
+	}
+	if !r.ds.HasElement(name) {
+		suggestions := r.ds.Suggest(name, 5)
+		if len(suggestions) > 0 {
+			return "", fmt.Errorf("elemen %q tidak ditemukan, mungkin maksudnya: %s", name, strings.Join(suggestions, ", "))
+		}
+		return "", fmt.Errorf("elemen %q tidak ditemukan", name)
+	}
+	return r.ds.DisplayName(name), nil
+}
+
+// Pecah argumen jadi dua nama elemen yang valid (buat path dan common)
+func (r *repl) twoElements(cmd string, args []string) (string, string, error) {
+	for i := 1; i < len(args); i++ {
+		a := strings.Join(args[:i], " ")
+		b := strings.Join(args[i:], " ")
+		if r.ds.HasElement(a) && r.ds.HasElement(b) {
+			return r.ds.DisplayName(a), r.ds.DisplayName(b), nil
+		}
+	}
+	return "", "", fmt.Errorf("pemakaian: %s <a> <b>, pastikan kedua elemen ada", cmd)
+}
+
+func (r *repl) execute(line string) (bool, error) {
+	args := splitArgs(line)
+	if len(args) == 0 {
+		return false, nil
+	}
+	cmd, args := strings.ToLower(args[0]), args[1:]
+
+	switch cmd {
+	case "quit", "exit":
+		return true, nil
+	case "help":
+		fmt.Fprint(r.out, replHelp)
+	case "recipes":
+		count := 1
+		if len(args) > 1 {
+			if n, err := strconv.Atoi(args[len(args)-1]); err == nil {
+				if n < 1 {
+					return false, errors.New("n harus >= 1")
+				}
+				count = n
+				args = args[:len(args)-1]
+			}
+		}
+		name, err := r.element(args)
+		if err != nil {
+			return false, err
+		}
+		trees, info, err := search(r.algorithm, name, r.ds, count, nil)
+		if err != nil {
+			return false, err
+		}
+		if len(trees) == 0 {
+			fmt.Fprintf(r.out, "Tidak ada resep untuk %s\n", name)
+		}
+		for i, t := range trees {
+			fmt.Fprintf(r.out, "Recipe %d:\n", i+1)
+			fmt.Fprint(r.out, render.TreeText(t, "  "))
+		}
+		metrics, _ := json.Marshal(info)
+		fmt.Fprintf(r.out, "(%s) %s\n", r.algorithm, metrics)
+	case "usedin":
+		name, err := r.element(args)
+		if err != nil {
+			return false, err
+		}
+		recipes := r.ds.UsedIn(name)
+		if len(recipes) == 0 {
+			fmt.Fprintf(r.out, "%s tidak dipakai di resep mana pun\n", name)
+		}
+		for _, e := range recipes {
+			fmt.Fprintf(r.out, "  %s + %s → %s (Tier %s)\n", e.Left, e.Right, e.Root, e.Tier)
+		}
+	case "path":
+		from, to, err := r.twoElements(cmd, args)
+		if err != nil {
+			return false, err
+		}
+		path, ok := r.ds.Path(from, to)
+		if !ok {
+			fmt.Fprintf(r.out, "Tidak ada jalur dari %s ke %s\n", from, to)
+			return false, nil
+		}
+		for i, e := range path {
+			fmt.Fprintf(r.out, "  %d. %s + %s → %s\n", i+1, e.Left, e.Right, e.Root)
+		}
+	case "common":
+		a, b, err := r.twoElements(cmd, args)
+		if err != nil {
+			return false, err
+		}
+		plan, missing := r.ds.PlanTwo(a, b)
+		if missing != "" {
+			return false, fmt.Errorf("%s tidak bisa dibuat dari base component", missing)
+		}
+		shared, _ := r.ds.SharedIngredients(a, b)
+		if len(shared) == 0 {
+			fmt.Fprintf(r.out, "Tidak ada elemen antara yang dipakai bareng %s dan %s\n", a, b)
+		}
+		for _, s := range shared {
+			fmt.Fprintf(r.out, "  %s (tier %d, %d langkah)\n", s.Element, s.Tier, s.Cost)
+		}
+		fmt.Fprintf(r.out, "Rencana gabungan (%d langkah, hemat %d):\n", plan.TotalSteps, plan.SavedSteps)
+		fmt.Fprint(r.out, render.PlanText(plan, "  "))
+	case "tier":
+		name, err := r.element(args)
+		if err != nil {
+			return false, err
+		}
+		tier, _ := r.ds.Tier(name)
+		fmt.Fprintf(r.out, "%s: tier %d\n", name, tier)
+	case "stats":
+		stats := r.ds.Stats()
+		fmt.Fprintf(r.out, "Dataset %s (%s)\n", r.ds.Name, r.ds.Version)
+		fmt.Fprintf(r.out, "  Elemen: %d\n  Resep: %d\n  Base component: %d\n  Tier maksimum: %d\n",
+			stats.Elements, stats.Recipes, stats.BaseComponents, stats.MaxTier)
+		for tier := 0; tier <= stats.MaxTier; tier++ {
+			fmt.Fprintf(r.out, "  Tier %d: %d elemen\n", tier, stats.ElementsByTier[tier])
+		}
+	case "algo":
+		if len(args) == 0 {
+			fmt.Fprintf(r.out, "Algoritma aktif: %s\n", r.algorithm)
+			return false, nil
+		}
+		algorithm := strings.ToLower(args[0])
+		if algorithm != "bfs" && algorithm != "dfs" {
+			return false, errors.New("algoritma harus bfs atau dfs")
+		}
+		r.algorithm = algorithm
+		fmt.Fprintf(r.out, "Algoritma aktif: %s\n", r.algorithm)
+	default:
+		return false, fmt.Errorf("perintah tidak dikenal: %s (ketik help)", cmd)
+	}
+	return false, nil
+}
+
+// Shell interaktif, pakai line editor kalau stdin terminal, kalau bukan baca per baris
+func runREPL(ds *Element.Dataset, algorithm string) error {
+	r := &repl{
+		ds:        ds,
+		algorithm: algorithm,
+		names:     ds.ElementNames(),
+		out:       os.Stdout,
+	}
+
+	var readLine func(prompt string) (string, error)
+	state, err := makeRaw(int(os.Stdin.Fd()))
+	if err == nil {
+		defer restoreTerminal(int(os.Stdin.Fd()), state)
+		editor := &lineEditor{
+			in:       bufio.NewReader(os.Stdin),
+			out:      os.Stdout,
+			complete: r.complete,
+		}
+		readLine = editor.ReadLine
+		fmt.Fprintf(r.out, "Dataset %s dimuat (%d elemen). Ketik help untuk bantuan.\n", ds.Name, len(r.names))
+	} else {
+		scanner := bufio.NewScanner(os.Stdin)
+		readLine = func(prompt string) (string, error) {
+			if !scanner.Scan() {
+				if scanner.Err() != nil {
+					return "", scanner.Err()
+				}
+				return "", io.EOF
+			}
+			return scanner.Text(), nil
+		}
+	}
+
+	for {
+		line, err := readLine("alchemy> ")
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		quit, err := r.execute(line)
+		if err != nil {
+			fmt.Fprintln(r.out, "error:", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
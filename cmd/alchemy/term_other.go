@@ -0,0 +1,16 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+type termState struct{}
+
+// Di luar linux REPL jalan tanpa Tab completion
+func makeRaw(fd int) (*termState, error) {
+	return nil, errors.New("raw mode terminal tidak didukung")
+}
+
+func restoreTerminal(fd int, state *termState) error {
+	return nil
+}
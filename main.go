@@ -1,16 +1,35 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	auth "stima-2-be/Auth"
+	cache "stima-2-be/Cache"
+	config "stima-2-be/Config"
+	"stima-2-be/Element"
 	handler "stima-2-be/Handler"
+	limit "stima-2-be/Limit"
+	logging "stima-2-be/Logging"
+	metrics "stima-2-be/Metrics"
+	snapshot "stima-2-be/Snapshot"
+	"stima-2-be/scrapper"
+	"syscall"
+	"time"
 )
 
+var corsOrigin = "*"
+
 func enableCORS(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Origin", corsOrigin)
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Request-ID, If-None-Match")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, X-Dataset-Version, ETag, X-Cache")
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusOK)
@@ -21,12 +40,181 @@ func enableCORS(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+type middleware func(http.HandlerFunc) http.HandlerFunc
+
+// Daftarin route dengan request ID/log, CORS dan pencatatan metrics,
+// middleware tambahan (rate limit dll) dipasang setelah CORS
+func handle(route string, h http.HandlerFunc, extra ...middleware) {
+	for i := len(extra) - 1; i >= 0; i-- {
+		h = extra[i](h)
+	}
+	http.HandleFunc(route, logging.RequestID(metrics.Instrument(route, enableCORS(h))))
+}
+
+// Terapkan config ke variabel package yang dipake di mana-mana
+func apply(cfg config.Config) {
+	corsOrigin = cfg.CORSOrigin
+	scrapper.WikiURL = cfg.WikiURL
+	scrapper.OutputFile = cfg.OutputFile
+	scrapper.DiffFile = cfg.DiffFile
+	snapshot.DataDir = cfg.SnapshotDir
+	snapshot.MaxSnapshots = cfg.MaxSnapshots
+	Element.PackDir = cfg.PackDir
+	Element.SetBaseComponents(cfg.BaseComponentMap())
+	handler.ReadyMinElements = cfg.ReadyMinElements
+	handler.BatchMaxQueries = cfg.BatchMaxQueries
+	handler.BatchConcurrency = cfg.BatchConcurrency
+	handler.BatchNodeBudget = int64(cfg.BatchNodeBudget)
+
+	// Cache dikosongin tiap dataset/pack berubah (scrape, aktivasi snapshot, upload pack)
+	handler.ResponseCache = cache.New(cfg.CacheMaxEntries, int64(cfg.CacheMaxBytes))
+	metrics.SetResponseCache(handler.ResponseCache)
+	Element.OnChange(handler.ResponseCache.Purge)
+}
+
 func main() {
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "konfigurasi tidak valid:", err)
+		os.Exit(2)
+	}
+	if cfg.PrintOnly {
+		cfg.Print(os.Stdout)
+		return
+	}
+	if err := logging.Setup(os.Stdout, cfg.LogLevel, cfg.LogFormat); err != nil {
+		fmt.Fprintln(os.Stderr, "konfigurasi log tidak valid:", err)
+		os.Exit(2)
+	}
+	apply(cfg)
+	slog.Info("konfigurasi efektif", append([]interface{}{"config_file", cfg.File}, cfg.LogAttrs()...)...)
+
+	if meta, err := snapshot.LoadActive(); err != nil {
+		slog.Warn("belum ada snapshot aktif", "err", err)
+	} else {
+		slog.Info("snapshot aktif", "version", meta.Version, "elements", meta.Elements)
+		metrics.SetLastScrape(meta.CreatedAt)
+	}
+
+	// Dataset tambahan (LA1, custom pack, dll)
+	loaded, err := Element.LoadDatasetsFromDir(cfg.DatasetDir)
+	if err != nil {
+		slog.Error("gagal load dataset", "err", err)
+	}
+	for _, ds := range loaded {
+		slog.Info("dataset dimuat", "dataset", ds.Name, "elements", len(ds.Elements))
+	}
+
+	packs, err := Element.LoadPacksFromDir(Element.PackDir)
+	if err != nil {
+		slog.Error("gagal load pack", "err", err)
+	}
+	for _, p := range packs {
+		slog.Info("pack dimuat", "pack", p.Name, "elements", len(p.Elements))
+	}
+
+	limits := cfg.Limits
+	clientLimiter := limit.NewLimiter(limits.Rate, limits.Burst)
+	scrapLimiter := limit.NewLimiter(limits.ScrapRate, limits.ScrapBurst)
+	searches := limit.NewSemaphore(limits.MaxConcurrent, limits.QueueSize, limits.QueueTimeout)
+
+	perClient := func(next http.HandlerFunc) http.HandlerFunc {
+		return limit.PerClient(clientLimiter, limits.TrustProxy, next)
+	}
+	perClientScrap := func(next http.HandlerFunc) http.HandlerFunc {
+		return limit.PerClient(scrapLimiter, limits.TrustProxy, next)
+	}
+	// Slot pencarian dipakai di handler search cuma kalau cache miss
+	handler.Searches = searches
+	metrics.SetSearchSlots(searches)
 
-	http.HandleFunc("/Scrap", enableCORS(handler.ScrapHandler))
-	http.HandleFunc("/BFS", enableCORS(handler.BFSHandler))
-	http.HandleFunc("/DFS", enableCORS(handler.DFSHandler))
+	keys, err := cfg.KeyStore()
+	if err != nil {
+		slog.Error("api key tidak valid", "err", err)
+		os.Exit(2)
+	}
+	if !keys.HasScope(auth.ScopeAdmin) {
+		slog.Warn("admin_api_keys kosong, endpoint admin (scrape, upload pack, snapshot) tidak bisa dipakai")
+	}
+	read := func(next http.HandlerFunc) http.HandlerFunc {
+		return auth.Require(keys, auth.ScopeRead, next)
+	}
+	admin := func(next http.HandlerFunc) http.HandlerFunc {
+		return auth.Require(keys, auth.ScopeAdmin, next)
+	}
+	readOrAdmin := func(next http.HandlerFunc) http.HandlerFunc {
+		return auth.ByMethod(keys, next)
+	}
+
+	// Limit scrape dipasang setelah auth biar request tanpa key ga ngabisin jatah admin
+	handle("/Scrap", handler.ScrapHandler, perClient, admin, perClientScrap)
+	handle("/BFS", handler.BFSHandler, perClient, read)
+	handle("/DFS", handler.DFSHandler, perClient, read)
+	handle("/v2/search", handler.V2SearchHandler, perClient, read)
+	handle("/batch", handler.BatchHandler, perClient, read)
+	handle("/openapi.json", handler.OpenAPIHandler)
+	handle("/Diff", handler.DiffHandler, perClient, read)
+	handle("/SVG", handler.SVGHandler, perClient, read)
+	handle("/Datasets", handler.DatasetListHandler, perClient, read)
+	handle("/Elements/search", handler.ElementSearchHandler, perClient, read)
+	handle("/Elements/common", handler.CommonHandler, perClient, read)
+	handle("/Packs", handler.PackHandler, perClient, readOrAdmin)
+	handle("/Snapshots", handler.SnapshotListHandler, perClient, read)
+	handle("/Snapshots/activate", handler.SnapshotActivateHandler, perClient, admin)
+	handle("/Snapshots/rollback", handler.SnapshotRollbackHandler, perClient, admin)
+	handle("/Cache", handler.CacheHandler, perClient, readOrAdmin)
+	handle("/metrics", metrics.Handler)
+
+	// Probe orchestrator dipanggil tiap beberapa detik, jadi ga ikut log request dan rate limit
+	http.HandleFunc("/healthz", metrics.Instrument("/healthz", handler.HealthzHandler))
+	http.HandleFunc("/readyz", metrics.Instrument("/readyz", handler.ReadyzHandler))
 
-	fmt.Println("Server is running on http://localhost:8080")
-	http.ListenAndServe(":8080", nil)
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+	if err := serve(server, cfg.ShutdownTimeout); err != nil {
+		slog.Error("server berhenti", "err", err)
+		os.Exit(1)
+	}
+}
+
+// Jalanin server sampai dapat SIGINT/SIGTERM, lalu tunggu request yang lagi jalan
+// (pencarian, scrape) selesai maksimal selama shutdownTimeout
+func serve(server *http.Server, shutdownTimeout time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("server is running", "addr", fmt.Sprintf("http://localhost%s", server.Addr))
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	slog.Info("shutdown dimulai, menunggu request yang sedang berjalan", "timeout", shutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		server.Close()
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("request belum selesai setelah %s, koneksi diputus paksa", shutdownTimeout)
+		}
+		return err
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	slog.Info("server berhenti dengan rapi")
+	return nil
 }
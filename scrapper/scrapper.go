@@ -2,28 +2,51 @@ package scrapper
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"regexp"
 	"stima-2-be/Element"
+	logging "stima-2-be/Logging"
+	snapshot "stima-2-be/Snapshot"
 	"strings"
 )
 
-func Scrapper() {
-	url := "https://little-alchemy.fandom.com/wiki/Elements_(Little_Alchemy_2)"
+// Bisa diganti lewat config
+var (
+	WikiURL = "https://little-alchemy.fandom.com/wiki/Elements_(Little_Alchemy_2)"
+	// output.json dari versi sebelum snapshot, ga ditulis lagi.
+	// Cuma dibaca sebagai pembanding diff kalau belum ada dataset yang di-load
+	OutputFile = "output.json"
+	DiffFile   = "diff.json"
+)
+
+// Error dari sisi wiki (jaringan, status bukan 200, halaman ga bisa diparse)
+var ErrFetch = errors.New("gagal mengambil data wiki")
 
-	resp, err := http.Get(url)
+// Scrape wiki dan simpan hasilnya sebagai snapshot baru
+func Scrapper(ctx context.Context) (snapshot.Meta, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, WikiURL, nil)
 	if err != nil {
-		panic(err)
+		return snapshot.Meta{}, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return snapshot.Meta{}, fmt.Errorf("%w: %v", ErrFetch, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return snapshot.Meta{}, fmt.Errorf("%w: status %s", ErrFetch, resp.Status)
+	}
+
 	buf := new(bytes.Buffer)
 	_, err = buf.ReadFrom(resp.Body)
 	if err != nil {
-		panic(err)
+		return snapshot.Meta{}, fmt.Errorf("%w: %v", ErrFetch, err)
 	}
 	html := buf.String()
 
@@ -50,17 +73,51 @@ func Scrapper() {
 		allElements = append(allElements, elements...)
 	}
 
-	jsonData, err := json.MarshalIndent(allElements, "", "  ")
-	if err != nil {
-		panic(err)
+	// Scrape kosong berarti struktur halaman berubah, jangan timpa data lama
+	if len(allElements) == 0 {
+		return snapshot.Meta{}, fmt.Errorf("%w: tidak ada elemen yang berhasil di-scrape", ErrFetch)
 	}
 
-	err = os.WriteFile("output.json", jsonData, 0644)
+	// Bandingin sama dataset yang lagi aktif
+	previous := previousElements(OutputFile)
+	diff := Element.DiffElements(previous, allElements)
+
+	meta, err := snapshot.Save(allElements)
 	if err != nil {
-		panic(err)
+		return snapshot.Meta{}, err
 	}
 
-	fmt.Println("Berhasil disimpan di output.json")
+	// Snapshot udah aman tersimpan, diff cuma pelengkap jadi gagal nulis ga batalin aktivasi
+	if err := Element.SaveDiffToFile(diff, DiffFile); err != nil {
+		logging.FromContext(ctx).Error("simpan diff gagal", "file", DiffFile, "version", meta.Version, "err", err)
+	}
+
+	logging.FromContext(ctx).Info("scrape disimpan",
+		"version", meta.Version,
+		"elements", meta.Elements,
+		"added_elements", len(diff.AddedElements),
+		"removed_elements", len(diff.RemovedElements),
+		"added_recipes", len(diff.AddedRecipes),
+		"removed_recipes", len(diff.RemovedRecipes),
+		"tier_changes", len(diff.TierChanges))
+	return meta, nil
+}
+
+// Dataset yang lagi di-load, kalau belum ada ambil dari output.json lama
+func previousElements(filename string) []Element.Element {
+	previous := Element.GetAllElement()
+	if len(previous) > 0 {
+		return previous
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, &previous); err != nil {
+		return nil
+	}
+	return previous
 }
 
 func extractElementsFromTable(tableHtml string, tier string) []Element.Element {